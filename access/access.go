@@ -21,16 +21,24 @@ const (
 	// The type is accessible only within its own package
 	AccessibleInsidePackage = 1
 
+	// The type is exported from an internal package, accessible only within the tree rooted at the parent of "internal"
+	AccessibleInsideInternalTree = 2
+
 	// The type is accessible from any package
-	AccessibleEverywhere = 2
+	AccessibleEverywhere = 3
 )
 
 type Namedness int
 
 const (
 	NamednessUndefined Namedness = iota
+	// The type has no name (after dereferencing pointers), or it is a generic instantiation with anonymous type arguments
 	AnonymousType
+	// The type has a name, both predeclared (int, string, error...) and defined in a package
 	NamedType
+	// The type is a named type defined in a package, not a predeclared one. It is a requirement level only (ex. reg.WithNamedness),
+	// Info reports defined types as NamedType, use [Classify] to tell them apart
+	DefinedType
 )
 
 func (t Accessibility) String() string {
@@ -41,6 +49,8 @@ func (t Accessibility) String() string {
 		return "not accessible"
 	case AccessibleInsidePackage:
 		return "accessible inside package"
+	case AccessibleInsideInternalTree:
+		return "accessible inside internal tree"
 	case AccessibleEverywhere:
 		return "accessible everywhere"
 	default:
@@ -57,6 +67,8 @@ func (t Namedness) String() string {
 		return "anonymous type"
 	case NamedType:
 		return "named type"
+	case DefinedType:
+		return "defined type"
 	default:
 		err, _ := fmt.Printf("unknown namedness: %d\n", t)
 		panic(err)
@@ -69,14 +81,36 @@ func Info[T any](_ T) (Namedness, Accessibility) {
 	return getNamedness(rt), getAccessability(rt)
 }
 
+// InfoOf is the [reflect.Type] variant of [Info]
+func InfoOf(rt reflect.Type) (Namedness, Accessibility) {
+	return getNamedness(rt), getAccessability(rt)
+}
+
+// InfoFrom is the variant of [InfoOf] with accessibility computed relative to the package pkgPath instead of the caller.
+// Results don't depend on the call stack, making them deterministic for wrappers and tests.
+//
+//	InfoFrom(reflect.TypeFor[*foo.bar](), "example.com/foo") // NamedType, AccessibleInsidePackage
+func InfoFrom(rt reflect.Type, pkgPath string) (Namedness, Accessibility) {
+	return getNamedness(rt), accessibilityFrom(rt, pkgPath)
+}
+
 func getAccessability(rt reflect.Type) Accessibility {
 	callerFunc := getCallerFuncName(3)
 	callerPkg := extractCallerPKG(callerFunc)
 
+	return accessibilityFrom(rt, callerPkg)
+}
+
+// accessibilityFrom returns the accessibility of rt as seen from the package callerPkg
+func accessibilityFrom(rt reflect.Type, callerPkg string) Accessibility {
 	if accessibleEverywhere(rt) {
 		return AccessibleEverywhere
 	}
 
+	if accessible(rt, callerPkg, false, make(map[reflect.Type]bool)) {
+		return AccessibleInsideInternalTree
+	}
+
 	if accessibleFromPackage(rt, callerPkg) {
 		return AccessibleInsidePackage
 	}
@@ -87,14 +121,16 @@ func getNamedness(rt reflect.Type) Namedness {
 	for rt != nil && rt.Kind() == reflect.Ptr {
 		rt = rt.Elem()
 	}
-	if rt != nil && rt.Name() != "" {
+	// generic instantiations are only named if all their type arguments are
+	if rt != nil && rt.Name() != "" && argsNamed(rt.Name()) {
 		return NamedType
 	}
 
 	return AnonymousType
 }
 
-func isExported(name string) bool {
+// IsExported reports whether the identifier name is exported, ex. the name of a type
+func IsExported(name string) bool {
 	if name == "" {
 		return false
 	}
@@ -105,7 +141,7 @@ func isExported(name string) bool {
 
 // accessibleEverywhere reports whether rt can be accessed from any package other than its defining one.
 //
-// For named types: only exported types are accessible (predeclared types are also accessible).
+// For named types: only exported types outside of internal packages are accessible (predeclared types are also accessible), for generic instantiations so must be their type arguments.
 //
 // For unnamed composite types: all referenced named types must be exported or predeclared.
 //
@@ -117,57 +153,77 @@ func accessibleEverywhere(rt reflect.Type, seen ...map[reflect.Type]bool) bool {
 		}
 	}
 
+	return accessible(rt, "", false, seen[0])
+}
+
+// acessableFromPackage reports whether rt can be accessed from the specified package.
+//
+// For named types: exported types are accessible from anywhere the package can be imported from; unexported types are accessible only within their defining package. Predeclared types are accessible.
+// Type arguments of generic instantiations must be accessible as well.
+//
+// For unnamed composite types: all referenced named types must be accessible from pkg.
+//
+//	accessibleFromPackage(rt) // do not pass the seen param
+func accessibleFromPackage(rt reflect.Type, pkgPath string, seen ...map[reflect.Type]bool) bool {
+	if seen == nil {
+		seen = []map[reflect.Type]bool{
+			make(map[reflect.Type]bool),
+		}
+	}
+
+	return accessible(rt, pkgPath, true, seen[0])
+}
+
+// accessible reports whether rt can be accessed from the package pkgPath, unexported types of pkgPath only if samePkg is set
+func accessible(rt reflect.Type, pkgPath string, samePkg bool, seen map[reflect.Type]bool) bool {
 	if rt == nil {
 		return true
 	}
 
-	if seen[0][rt] {
+	if seen[rt] {
 		return true
 	}
 
 	// avoid infinite recursion
-	seen[0][rt] = true
+	seen[rt] = true
 
 	for rt.Kind() == reflect.Ptr {
 		rt = rt.Elem()
 	}
 
 	if name := rt.Name(); name != "" {
-		// Predeclared types are accessible
-		if rt.PkgPath() == "" {
-			return true
-		}
-		return isExported(name)
+		// Type arguments of generic instantiations must be accessible as well
+		return namedAccessible(rt.PkgPath(), name, pkgPath, samePkg) && argsAccessibleFrom(rt, pkgPath, samePkg)
 	}
 
 	// Handle unnamed types
 	switch rt.Kind() {
 	case reflect.Slice, reflect.Array, reflect.Chan, reflect.Pointer:
-		return accessibleEverywhere(rt.Elem(), seen...)
+		return accessible(rt.Elem(), pkgPath, samePkg, seen)
 	case reflect.Map:
-		return accessibleEverywhere(rt.Key(), seen...) && accessibleEverywhere(rt.Elem(), seen...)
+		return accessible(rt.Key(), pkgPath, samePkg, seen) && accessible(rt.Elem(), pkgPath, samePkg, seen)
 	case reflect.Func:
 		for i := 0; i < rt.NumIn(); i++ {
-			if !accessibleEverywhere(rt.In(i), seen...) {
+			if !accessible(rt.In(i), pkgPath, samePkg, seen) {
 				return false
 			}
 		}
 		for i := 0; i < rt.NumOut(); i++ {
-			if !accessibleEverywhere(rt.Out(i), seen...) {
+			if !accessible(rt.Out(i), pkgPath, samePkg, seen) {
 				return false
 			}
 		}
 		return true
 	case reflect.Struct:
 		for i := 0; i < rt.NumField(); i++ {
-			if !accessibleEverywhere(rt.Field(i).Type, seen...) {
+			if !accessible(rt.Field(i).Type, pkgPath, samePkg, seen) {
 				return false
 			}
 		}
 		return true
 	case reflect.Interface:
 		for i := 0; i < rt.NumMethod(); i++ {
-			if !accessibleEverywhere(rt.Method(i).Type, seen...) {
+			if !accessible(rt.Method(i).Type, pkgPath, samePkg, seen) {
 				return false
 			}
 		}
@@ -177,83 +233,55 @@ func accessibleEverywhere(rt reflect.Type, seen ...map[reflect.Type]bool) bool {
 	}
 }
 
-// acessableFromPackage reports whether rt can be accessed from the specified package.
-//
-// For named types: exported types are accessible from anywhere; unexported types are accessible only within their defining package. Predeclared types are accessible.
-//
-// For unnamed composite types: all referenced named types must be accessible from pkg.
-//
-//	accessibleFromPackage(rt) // do not pass the seen param
-func accessibleFromPackage(rt reflect.Type, pkgPath string, seen ...map[reflect.Type]bool) bool {
-	if seen == nil {
-		seen = []map[reflect.Type]bool{
-			make(map[reflect.Type]bool),
-		}
-	}
-
-	if rt == nil {
+// namedAccessible reports whether the type name defined in typePkg can be accessed from the package pkgPath, unexported types only if samePkg is set
+func namedAccessible(typePkg, name, pkgPath string, samePkg bool) bool {
+	// Predeclared types are accessible
+	if typePkg == "" {
 		return true
 	}
 
-	if seen[0][rt] {
+	if samePkg && typePkg == pkgPath {
 		return true
 	}
 
-	// avoid infinite recursion
-	seen[0][rt] = true
+	return IsExported(name) && Importable(typePkg, pkgPath)
+}
 
-	for rt.Kind() == reflect.Ptr {
-		rt = rt.Elem()
+// importable reports whether the package typePkg can be imported by the package pkgPath, following the go internal package rule.
+// An empty pkgPath can only import packages outside of internal trees.
+//
+//	Importable("example.com/a/internal/b", "example.com/a/c") // true
+//	Importable("example.com/a/internal/b", "example.com/d")   // false
+func Importable(typePkg, pkgPath string) bool {
+	parent, ok := internalParent(typePkg)
+	if !ok {
+		return true
 	}
 
-	if name := rt.Name(); name != "" {
-		// Predeclared types are accessible
-		if rt.PkgPath() == "" {
-			return true
-		}
-		// Exported types are accessible from anywhere
-		if isExported(name) {
-			return true
-		}
+	if pkgPath == "" {
+		return false
+	}
 
-		// Unexported named types are accessible only within their package
-		return rt.PkgPath() == pkgPath
+	// internal packages of the standard library can only be imported by the standard library
+	if parent == "" {
+		first, _, _ := strings.Cut(pkgPath, "/")
+		return !strings.Contains(first, ".")
 	}
 
-	// Handle unnamed types
-	switch rt.Kind() {
-	case reflect.Slice, reflect.Array, reflect.Chan, reflect.Pointer:
-		return accessibleFromPackage(rt.Elem(), pkgPath, seen...)
-	case reflect.Map:
-		return accessibleFromPackage(rt.Key(), pkgPath, seen...) && accessibleFromPackage(rt.Elem(), pkgPath, seen...)
-	case reflect.Func:
-		for i := 0; i < rt.NumIn(); i++ {
-			if !accessibleFromPackage(rt.In(i), pkgPath, seen...) {
-				return false
-			}
-		}
-		for i := 0; i < rt.NumOut(); i++ {
-			if !accessibleFromPackage(rt.Out(i), pkgPath, seen...) {
-				return false
-			}
-		}
-		return true
-	case reflect.Struct:
-		for i := 0; i < rt.NumField(); i++ {
-			if !accessibleFromPackage(rt.Field(i).Type, pkgPath, seen...) {
-				return false
-			}
-		}
-		return true
-	case reflect.Interface:
-		for i := 0; i < rt.NumMethod(); i++ {
-			if !accessibleFromPackage(rt.Method(i).Type, pkgPath, seen...) {
-				return false
-			}
-		}
-		return true
+	return pkgPath == parent || strings.HasPrefix(pkgPath, parent+"/")
+}
+
+// internalParent returns the root of the tree allowed to import pkgPath, the parent of its last "internal" element
+func internalParent(pkgPath string) (string, bool) {
+	switch {
+	case strings.HasSuffix(pkgPath, "/internal"):
+		return strings.TrimSuffix(pkgPath, "/internal"), true
+	case strings.Contains(pkgPath, "/internal/"):
+		return pkgPath[:strings.LastIndex(pkgPath, "/internal/")], true
+	case pkgPath == "internal" || strings.HasPrefix(pkgPath, "internal/"):
+		return "", true
 	default:
-		return true
+		return "", false
 	}
 }
 
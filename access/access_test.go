@@ -2,7 +2,10 @@ package access
 
 import (
 	"reflect"
+	"slices"
 	"testing"
+
+	"github.com/mp3cko/registry/access/internal/accesstest"
 )
 
 // test-only helper types
@@ -41,7 +44,7 @@ func TestInfo_NamednessAndAccessibility(t *testing.T) {
 	}
 }
 
-func Test_isExported(t *testing.T) {
+func TestIsExported(t *testing.T) {
 	cases := []struct {
 		in   string
 		want bool
@@ -54,8 +57,8 @@ func Test_isExported(t *testing.T) {
 		{"Ligma", true},
 	}
 	for _, tc := range cases {
-		if got := isExported(tc.in); got != tc.want {
-			t.Fatalf("isExported(%q) = %v, want %v", tc.in, got, tc.want)
+		if got := IsExported(tc.in); got != tc.want {
+			t.Fatalf("IsExported(%q) = %v, want %v", tc.in, got, tc.want)
 		}
 	}
 }
@@ -88,6 +91,7 @@ func TestAccessibility_String(t *testing.T) {
 		{AccessibilityUndefined, "accessibility undefined"},
 		{NotAccessible, "not accessible"},
 		{AccessibleInsidePackage, "accessible inside package"},
+		{AccessibleInsideInternalTree, "accessible inside internal tree"},
 		{AccessibleEverywhere, "accessible everywhere"},
 	}
 	for _, tc := range cases {
@@ -105,6 +109,7 @@ func TestNamedness_String(t *testing.T) {
 		{NamednessUndefined, "namedness undefined"},
 		{AnonymousType, "anonymous type"},
 		{NamedType, "named type"},
+		{DefinedType, "defined type"},
 	}
 	for _, tc := range cases {
 		if got := tc.in.String(); got != tc.want {
@@ -226,3 +231,107 @@ func Test_getNamedness_InterfacesAndChannels(t *testing.T) {
 		t.Fatalf("getNamedness([3]string) = %v, want %v", got, AnonymousType)
 	}
 }
+
+func TestInfoOf(t *testing.T) {
+	cases := []struct {
+		rt    reflect.Type
+		wantN Namedness
+		wantA Accessibility
+	}{
+		{reflect.TypeFor[PublicType](), NamedType, AccessibleEverywhere},
+		{reflect.TypeFor[privateType](), NamedType, AccessibleInsidePackage},
+		{reflect.TypeFor[interface{ M() }](), AnonymousType, AccessibleEverywhere},
+		{reflect.TypeFor[*struct{ C container }](), AnonymousType, AccessibleInsidePackage},
+	}
+
+	for _, tc := range cases {
+		if n, a := InfoOf(tc.rt); n != tc.wantN || a != tc.wantA {
+			t.Fatalf("InfoOf(%s) = %v, %v; want %v, %v", tc.rt, n, a, tc.wantN, tc.wantA)
+		}
+	}
+}
+
+func TestInfoFrom(t *testing.T) {
+	thisPkg := reflect.TypeFor[privateType]().PkgPath()
+
+	cases := []struct {
+		rt    reflect.Type
+		pkg   string
+		wantN Namedness
+		wantA Accessibility
+	}{
+		{reflect.TypeFor[PublicType](), "example.com/other", NamedType, AccessibleEverywhere},
+		{reflect.TypeFor[privateType](), thisPkg, NamedType, AccessibleInsidePackage},
+		{reflect.TypeFor[privateType](), "example.com/other", NamedType, NotAccessible},
+		{reflect.TypeFor[*struct{ C container }](), "example.com/other", AnonymousType, NotAccessible},
+		{reflect.TypeFor[[]int](), "", AnonymousType, AccessibleEverywhere},
+	}
+
+	for _, tc := range cases {
+		if n, a := InfoFrom(tc.rt, tc.pkg); n != tc.wantN || a != tc.wantA {
+			t.Fatalf("InfoFrom(%s, %q) = %v, %v; want %v, %v", tc.rt, tc.pkg, n, a, tc.wantN, tc.wantA)
+		}
+	}
+}
+
+func TestImportable(t *testing.T) {
+	cases := []struct {
+		typePkg, pkgPath string
+		want             bool
+	}{
+		{"example.com/a/b", "example.com/c", true},
+		{"example.com/a/b", "", true},
+		{"example.com/a/internal", "example.com/a", true},
+		{"example.com/a/internal/b", "example.com/a/c/d", true},
+		{"example.com/a/internal/b", "example.com/ab", false},
+		{"example.com/a/internal/b", "example.com/d", false},
+		{"example.com/a/internal/b", "", false},
+		{"example.com/a/internal/b/internal/c", "example.com/a/c", false},
+		{"example.com/a/internal/b/internal/c", "example.com/a/internal/b/d", true},
+		{"internal/abi", "reflect", true},
+		{"internal/abi", "example.com/a", false},
+	}
+
+	for _, tc := range cases {
+		if got := Importable(tc.typePkg, tc.pkgPath); got != tc.want {
+			t.Fatalf("Importable(%q, %q) = %v; want %v", tc.typePkg, tc.pkgPath, got, tc.want)
+		}
+	}
+}
+
+func TestInfoFrom_Internal(t *testing.T) {
+	thisPkg := reflect.TypeFor[privateType]().PkgPath()
+
+	cases := []struct {
+		rt    reflect.Type
+		pkg   string
+		wantA Accessibility
+	}{
+		{reflect.TypeFor[accesstest.Exported](), thisPkg, AccessibleInsideInternalTree},
+		{reflect.TypeFor[[]*accesstest.Exported](), thisPkg + "/sub", AccessibleInsideInternalTree},
+		{reflect.TypeFor[List[accesstest.Exported]](), thisPkg, AccessibleInsideInternalTree},
+		{reflect.TypeFor[accesstest.Exported](), "github.com/mp3cko/registry", NotAccessible},
+		{reflect.TypeFor[struct {
+			A accesstest.Exported
+			B privateType
+		}](), thisPkg, AccessibleInsidePackage},
+	}
+
+	for _, tc := range cases {
+		if _, a := InfoFrom(tc.rt, tc.pkg); a != tc.wantA {
+			t.Fatalf("InfoFrom(%s, %q) accessibility = %v; want %v", tc.rt, tc.pkg, a, tc.wantA)
+		}
+	}
+
+	got := ExplainLevel(reflect.TypeFor[map[PublicType]*accesstest.Exported](), thisPkg, AccessibleEverywhere)
+	want := []string{"map value → *accesstest.Exported (internal)"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExplainLevel = %q; want %q", got, want)
+	}
+
+	got = ExplainLevel(reflect.TypeFor[struct{ A privateType }](), thisPkg, AccessibleInsideInternalTree)
+	want = []string{"field A → access.privateType (unexported)"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ExplainLevel = %q; want %q", got, want)
+	}
+}
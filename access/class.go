@@ -0,0 +1,87 @@
+package access
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Class is a set of classifications of a type, finer grained than [Namedness]. Classes are not exclusive, ex. *List[T] is a [PointerToNamed] [GenericInstantiation]
+type Class uint
+
+const (
+	// The type has no name (after dereferencing pointers)
+	Anonymous Class = 1 << iota
+	// The type is predeclared (int, string, error...)
+	Predeclared
+	// The type is a named type defined in a package
+	Defined
+	// The type is an instantiation of a generic type
+	GenericInstantiation
+	// The type is a pointer to a named type
+	PointerToNamed
+	// The type is an interface, named or not
+	Interface
+)
+
+// classNames in order of the class bits
+var classNames = []string{"anonymous", "predeclared", "defined", "generic instantiation", "pointer to named", "interface"}
+
+// Classify returns all the classes of rt
+//
+//	Classify(reflect.TypeFor[*bytes.Buffer]()) // Defined | PointerToNamed
+//	Classify(reflect.TypeFor[error]())         // Predeclared | Interface
+func Classify(rt reflect.Type) Class {
+	if rt == nil {
+		return 0
+	}
+
+	var class Class
+
+	named := rt
+	for named.Kind() == reflect.Ptr && named.Name() == "" {
+		named = named.Elem()
+	}
+
+	switch {
+	case named.Name() == "":
+		class |= Anonymous
+	case named.PkgPath() == "":
+		class |= Predeclared
+	default:
+		class |= Defined
+	}
+
+	if named.Name() != "" && named != rt {
+		class |= PointerToNamed
+	}
+
+	if strings.Contains(named.Name(), "[") {
+		class |= GenericInstantiation
+	}
+
+	if named.Kind() == reflect.Interface {
+		class |= Interface
+	}
+
+	return class
+}
+
+// Has reports whether all the classes in c are set
+func (t Class) Has(c Class) bool {
+	return t&c == c
+}
+
+func (t Class) String() string {
+	var names []string
+	for i, name := range classNames {
+		if t.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+
+	if names == nil {
+		return "unclassified"
+	}
+
+	return strings.Join(names, " | ")
+}
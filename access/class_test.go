@@ -0,0 +1,47 @@
+package access
+
+import (
+	"reflect"
+	"testing"
+)
+
+// ClassIface is a named interface
+type ClassIface interface{ M() }
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		rt   reflect.Type
+		want Class
+	}{
+		{reflect.TypeFor[int](), Predeclared},
+		{reflect.TypeFor[error](), Predeclared | Interface},
+		{reflect.TypeFor[any](), Anonymous | Interface},
+		{reflect.TypeFor[PublicType](), Defined},
+		{reflect.TypeFor[**PublicType](), Defined | PointerToNamed},
+		{reflect.TypeFor[*List[int]](), Defined | GenericInstantiation | PointerToNamed},
+		{reflect.TypeFor[ClassIface](), Defined | Interface},
+		{reflect.TypeFor[*struct{}](), Anonymous},
+		{reflect.TypeFor[[]PublicType](), Anonymous},
+		{nil, 0},
+	}
+
+	for _, tc := range cases {
+		if got := Classify(tc.rt); got != tc.want {
+			t.Fatalf("Classify(%v) = %v; want %v", tc.rt, got, tc.want)
+		}
+	}
+}
+
+func TestClass_String(t *testing.T) {
+	if got, want := (Defined | GenericInstantiation | PointerToNamed).String(), "defined | generic instantiation | pointer to named"; got != want {
+		t.Fatalf("Class.String() = %q; want %q", got, want)
+	}
+
+	if got, want := Class(0).String(), "unclassified"; got != want {
+		t.Fatalf("Class(0).String() = %q; want %q", got, want)
+	}
+
+	if !(Predeclared | Interface).Has(Interface) || Defined.Has(Defined|Interface) {
+		t.Fatalf("Class.Has mismatch")
+	}
+}
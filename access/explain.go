@@ -0,0 +1,119 @@
+package access
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Explain returns the path to every named type inside rt that is not accessible from the package pkgPath.
+// Each offending type is reported once, by the first path that reaches it.
+// An empty pkgPath explains why rt is not [AccessibleEverywhere]. Returns nil if rt is accessible.
+//
+//	Explain(reflect.TypeFor[map[string]func(*foo.bar) error](), "example.com/app")
+//	// ["map value → func param 0 → *foo.bar (unexported)"]
+func Explain(rt reflect.Type, pkgPath string) []string {
+	return ExplainLevel(rt, pkgPath, AccessibleInsidePackage)
+}
+
+// ExplainLevel is the variant of [Explain] explaining why rt doesn't have at least the accessibility level as seen from pkgPath.
+//
+//	ExplainLevel(reflect.TypeFor[app.Config](), "example.com/app", AccessibleEverywhere)
+//	// ["app.Config (unexported)"]
+func ExplainLevel(rt reflect.Type, pkgPath string, level Accessibility) []string {
+	if level == AccessibleEverywhere {
+		pkgPath = ""
+	}
+
+	e := explainer{
+		pkgPath: pkgPath,
+		samePkg: level <= AccessibleInsidePackage,
+		seen:    make(map[reflect.Type]bool),
+	}
+	e.explain(rt, nil)
+
+	return e.paths
+}
+
+// explainer collects the paths to inaccessible named types
+type explainer struct {
+	pkgPath string                // package the types are accessed from
+	samePkg bool                  // unexported types of pkgPath are accessible
+	paths   []string              // collected paths
+	seen    map[reflect.Type]bool // types already explained
+}
+
+// culprit appends the path to an inaccessible named type
+func (t *explainer) culprit(path []string, typ fmt.Stringer, name string) {
+	reason := "internal"
+	if !IsExported(name) {
+		reason = "unexported"
+	}
+
+	t.paths = append(t.paths, strings.Join(append(path[:len(path):len(path)], fmt.Sprintf("%s (%s)", typ, reason)), " → "))
+}
+
+// explain appends the path to every inaccessible named type in rt, path holds the steps taken to reach rt
+func (t *explainer) explain(rt reflect.Type, path []string) {
+	if rt == nil || t.seen[rt] {
+		return
+	}
+
+	// avoid infinite recursion
+	t.seen[rt] = true
+
+	named := rt
+	for named.Kind() == reflect.Ptr && named.Name() == "" {
+		named = named.Elem()
+	}
+
+	if name := named.Name(); name != "" {
+		if !namedAccessible(named.PkgPath(), name, t.pkgPath, t.samePkg) {
+			t.culprit(path, rt, name)
+		}
+
+		for i, arg := range typeArgs(name) {
+			for _, ident := range argIdents(arg) {
+				if !namedAccessible(ident.pkgPath, ident.name, t.pkgPath, t.samePkg) {
+					t.culprit(append(path[:len(path):len(path)], fmt.Sprintf("type arg %d", i)), ident, ident.name)
+				}
+			}
+		}
+
+		return
+	}
+
+	if named != rt {
+		path = append(path, "pointer")
+	}
+
+	// copy the path so that siblings don't share the backing array
+	step := func(s string) []string {
+		return append(path[:len(path):len(path)], s)
+	}
+
+	switch named.Kind() {
+	case reflect.Slice, reflect.Array:
+		t.explain(named.Elem(), step("elem"))
+	case reflect.Chan:
+		t.explain(named.Elem(), step("chan elem"))
+	case reflect.Map:
+		t.explain(named.Key(), step("map key"))
+		t.explain(named.Elem(), step("map value"))
+	case reflect.Func:
+		for i := 0; i < named.NumIn(); i++ {
+			t.explain(named.In(i), step(fmt.Sprintf("func param %d", i)))
+		}
+		for i := 0; i < named.NumOut(); i++ {
+			t.explain(named.Out(i), step(fmt.Sprintf("func result %d", i)))
+		}
+	case reflect.Struct:
+		for i := 0; i < named.NumField(); i++ {
+			t.explain(named.Field(i).Type, step("field "+named.Field(i).Name))
+		}
+	case reflect.Interface:
+		for i := 0; i < named.NumMethod(); i++ {
+			t.explain(named.Method(i).Type, step("method "+named.Method(i).Name))
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package access
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// hiddenID is an unexported type only reachable through a method
+type hiddenID int
+
+func TestExplain(t *testing.T) {
+	thisPkg := reflect.TypeFor[privateType]().PkgPath()
+
+	cases := []struct {
+		name string
+		rt   reflect.Type
+		pkg  string
+		want []string
+	}{
+		{"exported", reflect.TypeFor[PublicType](), "", nil},
+		{"predeclared", reflect.TypeFor[map[string]int](), "", nil},
+		{"same package", reflect.TypeFor[*privateType](), thisPkg, nil},
+		{"named", reflect.TypeFor[privateType](), "", []string{"access.privateType (unexported)"}},
+		{"pointer", reflect.TypeFor[*privateType](), "", []string{"*access.privateType (unexported)"}},
+		{
+			"map func param",
+			reflect.TypeFor[map[string]func(*privateType) error](), "example.com/other",
+			[]string{"map value → func param 0 → *access.privateType (unexported)"},
+		},
+		{
+			"multiple",
+			reflect.TypeFor[*struct {
+				A []privateType
+				B chan container
+				C interface{ M() hiddenID }
+				D privateType // already reported through A
+			}](), "",
+			[]string{
+				"pointer → field A → elem → access.privateType (unexported)",
+				"pointer → field B → chan elem → access.container (unexported)",
+				"pointer → field C → method M → func result 0 → access.hiddenID (unexported)",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Explain(tc.rt, tc.pkg); !slices.Equal(got, tc.want) {
+				t.Fatalf("Explain(%s, %q) = %q; want %q", tc.rt, tc.pkg, got, tc.want)
+			}
+		})
+	}
+}
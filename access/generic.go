@@ -0,0 +1,102 @@
+package access
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// reflect doesn't expose the type arguments of generic instantiations, they are only present in the type name
+// with fully qualified package paths. ex. "List[example.com/pkg.secret]" or "Pair[int,map[string]*example.com/pkg.T]"
+var (
+	// qualifiedIdentRe matches a package qualified identifier, capturing the package path and the identifier
+	qualifiedIdentRe = regexp.MustCompile(`([\w./~+-]+)\.([\pL_][\pL\pN_·]*)`)
+
+	// namedArgRe matches a type argument which is a (possibly qualified, pointer to) named type, capturing its own type arguments
+	namedArgRe = regexp.MustCompile(`^\**(?:[\w./~+-]+\.)?[\pL_][\pL\pN_·]*(\[.*\])?$`)
+
+	// quotedRe matches struct tags, their contents are not types
+	quotedRe = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+// qualifiedIdent is a named type referenced by a type argument
+type qualifiedIdent struct {
+	pkgPath string
+	name    string
+}
+
+// String returns the identifier the same way reflect.Type.String does, qualified only by the package name
+func (t qualifiedIdent) String() string {
+	return t.pkgPath[strings.LastIndex(t.pkgPath, "/")+1:] + "." + t.name
+}
+
+// typeArgs returns the type arguments of a generic instantiation from its name. ex. ["int", "map[string]*example.com/pkg.T"] for "Pair[int,map[string]*example.com/pkg.T]"
+func typeArgs(name string) []string {
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return nil
+	}
+
+	var (
+		args  []string
+		depth int
+		start = open + 1
+	)
+
+	list := name[:len(name)-1]
+	for i := start; i < len(list); i++ {
+		switch list[i] {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(args, strings.TrimSpace(list[start:]))
+}
+
+// argIdents returns every named type referenced by the type argument, including ones nested in other instantiations
+func argIdents(arg string) []qualifiedIdent {
+	var idents []qualifiedIdent
+	for _, m := range qualifiedIdentRe.FindAllStringSubmatch(quotedRe.ReplaceAllString(arg, ""), -1) {
+		idents = append(idents, qualifiedIdent{pkgPath: m[1], name: m[2]})
+	}
+
+	return idents
+}
+
+// argsAccessibleFrom reports whether every type argument of the named type rt is accessible from pkgPath, unexported types of pkgPath only if samePkg is set
+func argsAccessibleFrom(rt reflect.Type, pkgPath string, samePkg bool) bool {
+	for _, arg := range typeArgs(rt.Name()) {
+		for _, ident := range argIdents(arg) {
+			if !namedAccessible(ident.pkgPath, ident.name, pkgPath, samePkg) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// argsNamed reports whether every type argument in the type name is named, recursing into nested instantiations
+func argsNamed(name string) bool {
+	for _, arg := range typeArgs(name) {
+		// any is the only anonymous type that is trivial to spell
+		if arg == "interface {}" {
+			continue
+		}
+
+		m := namedArgRe.FindStringSubmatch(arg)
+		if m == nil || !argsNamed(m[1]) {
+			return false
+		}
+	}
+
+	return true
+}
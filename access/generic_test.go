@@ -0,0 +1,78 @@
+package access
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// generic test-only helper types
+type List[T any] struct{ v []T }
+type Pair[K comparable, V any] struct {
+	k K
+	v V
+}
+
+func TestTypeArgs(t *testing.T) {
+	cases := []struct {
+		name string
+		want []string
+	}{
+		{"PublicType", nil},
+		{"List[int]", []string{"int"}},
+		{"Pair[int,map[string]*example.com/pkg.List[example.com/pkg.T]]", []string{"int", "map[string]*example.com/pkg.List[example.com/pkg.T]"}},
+		{"Pair[func(int, string) error,struct { A int; B int }]", []string{"func(int, string) error", "struct { A int; B int }"}},
+	}
+
+	for _, tc := range cases {
+		if got := typeArgs(tc.name); !slices.Equal(got, tc.want) {
+			t.Fatalf("typeArgs(%q) = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestInfoFrom_Generic(t *testing.T) {
+	thisPkg := reflect.TypeFor[privateType]().PkgPath()
+	other := "example.com/other"
+
+	cases := []struct {
+		name  string
+		rt    reflect.Type
+		pkg   string
+		wantN Namedness
+		wantA Accessibility
+	}{
+		{"exported arg", reflect.TypeFor[List[PublicType]](), other, NamedType, AccessibleEverywhere},
+		{"predeclared arg", reflect.TypeFor[Pair[int, string]](), other, NamedType, AccessibleEverywhere},
+		{"other package arg", reflect.TypeFor[List[reflect.Type]](), other, NamedType, AccessibleEverywhere},
+		{"any arg", reflect.TypeFor[List[any]](), other, NamedType, AccessibleEverywhere},
+		{"unexported arg", reflect.TypeFor[List[privateType]](), thisPkg, NamedType, AccessibleInsidePackage},
+		{"unexported arg from other package", reflect.TypeFor[*List[privateType]](), other, NamedType, NotAccessible},
+		{"nested", reflect.TypeFor[Pair[int, map[string]*List[privateType]]](), thisPkg, AnonymousType, AccessibleInsidePackage},
+		{"nested from other package", reflect.TypeFor[Pair[int, map[string]*List[privateType]]](), other, AnonymousType, NotAccessible},
+		{"nested named", reflect.TypeFor[List[List[*PublicType]]](), other, NamedType, AccessibleEverywhere},
+		{"nested anonymous", reflect.TypeFor[List[List[struct{}]]](), other, AnonymousType, AccessibleEverywhere},
+		{"anonymous arg", reflect.TypeFor[List[func(privateType) error]](), other, AnonymousType, NotAccessible},
+		{"struct tag", reflect.TypeFor[List[struct {
+			A int `json:"x.y"`
+		}]](), other, AnonymousType, AccessibleEverywhere},
+		{"anonymous composite of instantiations", reflect.TypeFor[[]List[privateType]](), other, AnonymousType, NotAccessible},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if n, a := InfoFrom(tc.rt, tc.pkg); n != tc.wantN || a != tc.wantA {
+				t.Fatalf("InfoFrom(%s, %q) = %v, %v; want %v, %v", tc.rt, tc.pkg, n, a, tc.wantN, tc.wantA)
+			}
+		})
+	}
+}
+
+func TestExplain_Generic(t *testing.T) {
+	got := Explain(reflect.TypeFor[map[string]Pair[int, *List[privateType]]](), "")
+	want := []string{"map value → type arg 1 → access.privateType (unexported)"}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("Explain = %q; want %q", got, want)
+	}
+}
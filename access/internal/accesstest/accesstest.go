@@ -0,0 +1,5 @@
+// Package accesstest provides types of an internal package for testing the access package.
+package accesstest
+
+// Exported is exported, but only importable from within the access package tree
+type Exported struct{}
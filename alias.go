@@ -0,0 +1,76 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SetAs registers val under T and every type in as at once, so the same instance can be retrieved using any of them:
+//
+//	err := SetAs(pg, []reflect.Type{reflect.TypeFor[UserStore](), reflect.TypeFor[io.Closer]()}, WithAlias("primary"))
+//
+//	Get[*pgStore]()                  // pg
+//	Get[UserStore]()                 // pg
+//	Get[io.Closer](WithName("primary")) // pg
+//
+// Each type in as must be an interface implemented by val or the dynamic type of val itself, otherwise ErrNotAssignable is returned.
+// All types are checked against the registry constraints (accessibility, namedness, uniqueness) before anything is registered.
+//
+// The instance registered under T (and the name) is canonical, unsetting it also unsets all the additional types and aliases.
+// Supports the same options as [Set].
+func SetAs[T any](val T, as []reflect.Type, opts ...Option) error {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpSet, rt, opts)
+	if err != nil {
+		return err
+	}
+
+	if r.callOptions.conditions != nil {
+		return setConditional(r, rt, val, as)
+	}
+
+	defer r.cleanup()
+
+	err = checkMutationOpts(OpSet, r.callOptions)
+	if err == nil {
+		err = setEntry(r, rt, val, as, false)
+	}
+
+	r.observe(OpSet, rt, err)
+
+	return err
+}
+
+// keptLinks returns the links (aliases and additional types) of key an explicit replacement keeps, which it does unless new ones are given.
+// Caller must hold the lock and the call options
+func (t *registry) keptLinks(key entryKey, as []reflect.Type, replace bool) []entryKey {
+	co := t.callOptions
+	if !replace || as != nil || co.aliases != nil || co.profile != "" {
+		return nil
+	}
+
+	if m := t.meta[key]; m != nil && m.canonical == nil {
+		return m.links
+	}
+
+	return nil
+}
+
+// assignable checks that val can be retrieved as rt
+func assignable(val any, rt reflect.Type) error {
+	if rt == nil {
+		return fmt.Errorf("SetAs nil type: %w", ErrBadOption)
+	}
+
+	dyn := reflect.TypeOf(val)
+	if dyn == nil {
+		return fmt.Errorf("SetAs '%s' failed: %w, nil value", rt, ErrNotAssignable)
+	}
+
+	if rt.Kind() == reflect.Interface && dyn.Implements(rt) || dyn == rt {
+		return nil
+	}
+
+	return fmt.Errorf("SetAs '%s' failed: %w, '%s' does not implement it", rt, ErrNotAssignable, dyn)
+}
@@ -0,0 +1,126 @@
+package reg
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// AliasStore is a test interface implemented by *aliasStore
+type AliasStore interface{ Users() int }
+
+type aliasStore struct{ n int }
+
+func (t *aliasStore) Users() int   { return t.n }
+func (t *aliasStore) Close() error { return nil }
+
+func TestSetAs_MultipleTypes(t *testing.T) {
+	r := newTestReg(t)
+	pg := &aliasStore{n: 3}
+
+	as := []reflect.Type{reflect.TypeFor[AliasStore](), reflect.TypeFor[io.Closer]()}
+	if err := SetAs(pg, as, WithRegistry(r).WithName("primary").WithAlias("default")); err != nil {
+		t.Fatalf("SetAs error = %v", err)
+	}
+
+	for _, name := range []string{"primary", "default"} {
+		if got := MustGet[*aliasStore](WithRegistry(r).WithName(name)); got != pg {
+			t.Fatalf("Get[*aliasStore](%q) = %p, want %p", name, got, pg)
+		}
+
+		if got := MustGet[AliasStore](WithRegistry(r).WithName(name)); got != pg {
+			t.Fatalf("Get[AliasStore](%q) = %v, want %p", name, got, pg)
+		}
+
+		if got := MustGet[io.Closer](WithRegistry(r).WithName(name)); got != pg {
+			t.Fatalf("Get[io.Closer](%q) = %v, want %p", name, got, pg)
+		}
+	}
+
+	entries, err := Entries(WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Entries error = %v", err)
+	}
+
+	var aliases int
+	for _, e := range entries {
+		if e.AliasOf != nil {
+			aliases++
+
+			if e.AliasOf.Type != reflect.TypeFor[*aliasStore]() || e.AliasOf.Name != "primary" {
+				t.Fatalf("alias %v points to %v, want *aliasStore primary", e, e.AliasOf)
+			}
+		}
+	}
+
+	if len(entries) != 6 || aliases != 5 {
+		t.Fatalf("Entries = %v, want 6 entries with 5 aliases", entries)
+	}
+
+	// unsetting an alias only removes the alias
+	MustUnset[io.Closer](nil, WithRegistry(r).WithName("default"))
+	if _, err := Get[io.Closer](WithRegistry(r).WithName("primary")); err != nil {
+		t.Fatalf("Get after unsetting alias error = %v", err)
+	}
+
+	// unsetting the canonical instance removes all the aliases
+	MustUnset(pg, WithRegistry(r).WithName("primary"))
+	if all := MustGetAll(WithRegistry(r)); len(all) != 0 {
+		t.Fatalf("entries left after unsetting canonical instance: %v", all)
+	}
+}
+
+func TestSetAs_Errors(t *testing.T) {
+	r := newTestReg(t, WithUniqueType())
+
+	if err := SetAs(ExportedNamedTester{}, []reflect.Type{reflect.TypeFor[io.Closer]()}, WithRegistry(r)); !errors.Is(err, ErrNotAssignable) {
+		t.Fatalf("SetAs non implemented interface err = %v, want ErrNotAssignable", err)
+	}
+
+	if err := SetAs[AliasStore](nil, []reflect.Type{reflect.TypeFor[io.Closer]()}, WithRegistry(r)); !errors.Is(err, ErrNotAssignable) {
+		t.Fatalf("SetAs nil value err = %v, want ErrNotAssignable", err)
+	}
+
+	MustSet[io.Closer](&aliasStore{}, WithRegistry(r))
+
+	// atomic: the io.Closer conflict must prevent registering *aliasStore too
+	err := SetAs(&aliasStore{}, []reflect.Type{reflect.TypeFor[io.Closer]()}, WithRegistry(r))
+	if !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("SetAs conflicting type err = %v, want ErrNotUniqueType", err)
+	}
+
+	if _, err := Get[*aliasStore](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("SetAs partially applied, Get err = %v", err)
+	}
+
+	// aliases do not count as additional instances of the type
+	r2 := newTestReg(t)
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r2).WithAlias("a", "b"))
+	if _, err := Get[ExportedNamedTester](WithRegistry(r2).WithUniqueType().WithName("a")); err != nil {
+		t.Fatalf("Get WithUniqueType of aliased instance error = %v", err)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r2).WithAlias("x")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Get WithAlias err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := NewRegistry(WithAlias("x")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("NewRegistry WithAlias err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestSet_OverwriteDropsLinks(t *testing.T) {
+	r := newTestReg(t)
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r).WithAlias("old"))
+	MustSet(ExportedNamedTester{ID: 2}, WithRegistry(r))
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r).WithName("old")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("alias of replaced instance still present, err = %v", err)
+	}
+
+	if len(r.meta) != 0 {
+		t.Fatalf("metadata left after replacing instance: %v", r.meta)
+	}
+}
@@ -0,0 +1,86 @@
+package reg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mp3cko/registry/access"
+)
+
+type callerTester struct{ ID int }
+
+// setFromWrapper is a wrapper around Set, skipping its own frame
+func setFromWrapper[T any](val T, skip int, opts ...Option) error {
+	return Set(val, append(opts, WithCallerSkip(skip))...)
+}
+
+func TestCaller_WithCallerPackage(t *testing.T) {
+	r := newTestReg(t, WithAccessibility(access.AccessibleInsidePackage))
+
+	if err := Set(callerTester{}, WithRegistry(r), WithCallerPackage("example.com/other")); !errors.Is(err, ErrAccessibilityTooLow) {
+		t.Fatalf("Set from other package err = %v, want ErrAccessibilityTooLow", err)
+	}
+
+	if err := Set(callerTester{}, WithRegistry(r), WithCallerPackage(thisPackage)); err != nil {
+		t.Fatalf("Set from this package error = %v", err)
+	}
+}
+
+func TestCaller_WithCallerSkip(t *testing.T) {
+	r := newTestReg(t, WithAccessibility(access.AccessibleInsidePackage))
+
+	// wrapper -> this test, both inside this package
+	if err := setFromWrapper(callerTester{}, 1, WithRegistry(r)); err != nil {
+		t.Fatalf("Set skipping the wrapper error = %v", err)
+	}
+
+	// wrapper -> this test -> testing.tRunner
+	if err := setFromWrapper(callerTester{}, 2, WithRegistry(r), WithName("runner")); !errors.Is(err, ErrAccessibilityTooLow) {
+		t.Fatalf("Set relative to the testing package err = %v, want ErrAccessibilityTooLow", err)
+	}
+}
+
+func TestCaller_Options(t *testing.T) {
+	r := newTestReg(t)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want error
+	}{
+		{"empty package", []Option{WithCallerPackage("")}, ErrBadOption},
+		{"negative skip", []Option{WithCallerSkip(-1)}, ErrBadOption},
+		{"package and skip", []Option{WithCallerPackage(thisPackage), WithCallerSkip(1)}, ErrBadOption},
+		{"skip and package", []Option{WithCallerSkip(1).WithCallerPackage(thisPackage)}, ErrBadOption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Set(callerTester{}, append(tt.opts, WithRegistry(r))...); !errors.Is(err, tt.want) {
+				t.Fatalf("Set err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewRegistry(WithCallerSkip(1)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("NewRegistry(WithCallerSkip) err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := NewRegistry(WithCallerPackage(thisPackage)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("NewRegistry(WithCallerPackage) err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestCaller_AccessibilityExplained(t *testing.T) {
+	r := newTestReg(t, WithAccessibility(access.AccessibleInsidePackage))
+
+	err := Set(map[string]func(*callerTester) error{}, WithRegistry(r), WithCallerPackage("example.com/other"))
+	if !errors.Is(err, ErrAccessibilityTooLow) {
+		t.Fatalf("Set err = %v, want ErrAccessibilityTooLow", err)
+	}
+
+	if want := "map value → func param 0 → *reg.callerTester (unexported)"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("error %q does not contain explanation %q", err, want)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// registryPkg is the import path of the registry package
+const registryPkg = "github.com/mp3cko/registry"
+
+// entry is a registry entry declared by a struct field
+type entry struct {
+	Field    string // field name, used for the generated identifiers
+	Type     string // field type as written in the declaration
+	Name     string // instance name, empty for the default name
+	Optional bool   // not checked by Validate
+}
+
+// declaration is the parsed declaration struct
+type declaration struct {
+	Package  string
+	Type     string
+	Validate string   // name of the function validating all the entries, empty to skip it
+	Std      []string // standard library import specs used by the entry types
+	Imports  []string // other import specs used by the entry types
+	Entries  []entry
+}
+
+// HasRequired reports whether Validate checks any entry
+func (t declaration) HasRequired() bool {
+	return slices.ContainsFunc(t.Entries, func(e entry) bool { return !e.Optional })
+}
+
+// StdImports returns the standard library import specs of the generated code and the entry types
+func (t declaration) StdImports() []string {
+	specs := []string{strconv.Quote("errors")}
+	if t.HasRequired() {
+		specs = append(specs, strconv.Quote("fmt"))
+	}
+
+	for _, spec := range t.Std {
+		if !slices.Contains(specs, spec) {
+			specs = append(specs, spec)
+		}
+	}
+
+	return specs
+}
+
+// generate parses the package in dir and returns the formatted accessors of the declaration struct typeName, skipping the previous output file.
+// validate names the entry point validating all the entries, it calls Validate<typeName>
+func generate(dir, typeName, output, validate string) ([]byte, error) {
+	decl, err := parseDeclaration(dir, typeName, output)
+	if err != nil {
+		return nil, err
+	}
+
+	decl.Validate = validate
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, decl); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// parseDeclaration finds the declaration struct in the non test files of dir
+func parseDeclaration(dir, typeName, output string) (*declaration, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") || filepath.Base(name) == output {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range f.Decls {
+			gen, ok := d.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if ts.Name.Name != typeName {
+					continue
+				}
+
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					return nil, fmt.Errorf("%s: %s is not a struct", fset.Position(ts.Pos()), typeName)
+				}
+
+				return newDeclaration(fset, f, typeName, st)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+}
+
+// newDeclaration collects the entries of the declaration struct and the imports they use
+func newDeclaration(fset *token.FileSet, f *ast.File, typeName string, st *ast.StructType) (*declaration, error) {
+	decl := &declaration{Package: f.Name.Name, Type: typeName}
+	used := make(map[string]bool) // package identifiers used by the entry types
+
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded fields are not supported", fset.Position(field.Pos()))
+		}
+
+		var typ bytes.Buffer
+		if err := printer.Fprint(&typ, fset, field.Type); err != nil {
+			return nil, err
+		}
+
+		ast.Inspect(field.Type, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+
+			return true
+		})
+
+		var name string
+		var optional bool
+		if field.Tag != nil {
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			var opts string
+			name, opts, _ = strings.Cut(reflect.StructTag(tag).Get("reg"), ",")
+			optional = opts == "optional"
+		}
+
+		for _, ident := range field.Names {
+			decl.Entries = append(decl.Entries, entry{Field: ident.Name, Type: typ.String(), Name: name, Optional: optional})
+		}
+	}
+
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		if !used[name] {
+			continue
+		}
+
+		spec := strconv.Quote(importPath)
+		if imp.Name != nil {
+			spec = name + " " + spec
+		}
+
+		// standard library import paths have no dot in their first element
+		if first, _, _ := strings.Cut(importPath, "/"); !strings.Contains(first, ".") {
+			decl.Std = append(decl.Std, spec)
+			continue
+		}
+
+		decl.Imports = append(decl.Imports, spec)
+	}
+
+	return decl, nil
+}
+
+var tmpl = template.Must(template.New("reg").Parse(`// Code generated by reggen -type={{.Type}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+
+	reg "` + registryPkg + `"
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+
+// Registry keys of the {{.Type}} entries
+var (
+{{- range .Entries}}
+	{{.Field}}Key = reg.NewKey[{{.Type}}]({{printf "%q" .Name}})
+{{- end}}
+)
+{{range .Entries}}
+// Get{{.Field}} returns the {{.Field}} entry
+func Get{{.Field}}(opts ...reg.Option) ({{.Type}}, error) {
+	return {{.Field}}Key.Get(opts...)
+}
+
+// MustGet{{.Field}} returns the {{.Field}} entry, panics on error
+func MustGet{{.Field}}(opts ...reg.Option) {{.Type}} {
+	return {{.Field}}Key.MustGet(opts...)
+}
+
+// Set{{.Field}} registers the {{.Field}} entry
+func Set{{.Field}}(val {{.Type}}, opts ...reg.Option) error {
+	return {{.Field}}Key.Set(val, opts...)
+}
+{{end}}
+// Validate{{.Type}} reports every required {{.Type}} entry missing from the registry
+func Validate{{.Type}}(opts ...reg.Option) error {
+	var errs []error
+{{- range .Entries}}{{if not .Optional}}
+	if _, err := {{.Field}}Key.Get(opts...); err != nil {
+		errs = append(errs, fmt.Errorf("{{.Field}}: %w", err))
+	}
+{{- end}}{{end}}
+
+	return errors.Join(errs...)
+}
+{{- if .Validate}}
+
+// {{.Validate}} reports every required entry missing from the registry, call it at the end of bootstrap
+func {{.Validate}}(opts ...reg.Option) error {
+	return Validate{{.Type}}(opts...)
+}
+{{- end}}
+`))
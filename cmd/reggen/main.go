@@ -0,0 +1,71 @@
+// Command reggen generates typed registry accessors from a declaration struct, so that instance names are spelled once
+// instead of scattered Get[T](WithName("...")) calls.
+//
+// Every field of the struct declares an entry: the field type is the registry type and the optional reg tag holds the instance name.
+// Entries tagged optional are not checked by the generated Validate function.
+//
+//	//go:generate go run github.com/mp3cko/registry/cmd/reggen -type=Entries
+//
+//	type Entries struct {
+//		UserStore UserStore                  // default name
+//		PrimaryDB *sql.DB `reg:"primary"`    // named
+//		Cache     Cache   `reg:",optional"`  // not required by Validate
+//	}
+//
+// For each entry reggen emits a typed key (UserStoreKey), GetUserStore, MustGetUserStore and SetUserStore,
+// plus Validate reporting all the required entries missing from a registry. Validate calls ValidateEntries, named after the declaration struct,
+// so packages declaring multiple structs rename or skip the entry point using -validate and call the per struct functions instead.
+//
+// Usage:
+//
+//	reggen -type=Entries [-output=entries_reg.go] [-validate=Validate] [dir]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the declaration struct (required)")
+	output := flag.String("output", "", "output file name, defaults to <type>_reg.go in the package directory")
+	validate := flag.String("validate", "Validate", "name of the function validating all the entries, empty to skip it")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: reggen -type=Entries [-output=file] [-validate=name] [dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeName == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	out := *output
+	if out == "" {
+		out = strings.ToLower(*typeName) + "_reg.go"
+	}
+
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+
+	src, err := generate(dir, *typeName, filepath.Base(out), *validate)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "reggen:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "reggen:", err)
+		os.Exit(1)
+	}
+}
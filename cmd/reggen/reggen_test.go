@@ -0,0 +1,155 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testModule declares entries and uses the accessors generated for them
+var testModule = map[string]string{
+	"go.mod": `module example.com/app
+
+go 1.24
+
+require github.com/mp3cko/registry v0.0.0
+
+replace github.com/mp3cko/registry => REGISTRY
+`,
+	"store/store.go": `package store
+
+type DB struct{ DSN string }
+`,
+	"decl.go": `package app
+
+import (
+	"fmt"
+	"io"
+
+	st "example.com/app/store"
+)
+
+type UserStore interface{ Users() []string }
+
+type Entries struct {
+	UserStore    UserStore
+	PrimaryDB    *st.DB ` + "`reg:\"primary\"`" + `
+	Primary, Log io.Writer ` + "`reg:\"out,optional\"`" + `
+	Name         fmt.Stringer
+}
+`,
+	"cmd/main.go": `package main
+
+import (
+	"fmt"
+
+	"example.com/app"
+	"example.com/app/store"
+)
+
+func main() {
+	fmt.Println(app.Validate())
+
+	if err := app.SetPrimaryDB(&store.DB{DSN: "pg"}); err != nil {
+		panic(err)
+	}
+
+	fmt.Println(app.MustGetPrimaryDB().DSN, app.PrimaryDBKey)
+}
+`,
+}
+
+func TestGenerate(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	for name, src := range testModule {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(strings.ReplaceAll(src, "REGISTRY", root)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src, err := generate(dir, "Entries", "entries_reg.go", "Validate")
+	if err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by reggen -type=Entries; DO NOT EDIT.",
+		`st "example.com/app/store"`,
+		`PrimaryDBKey = reg.NewKey[*st.DB]("primary")`,
+		`UserStoreKey = reg.NewKey[UserStore]("")`,
+		"func GetUserStore(opts ...reg.Option) (UserStore, error)",
+		"func MustGetPrimaryDB(opts ...reg.Option) *st.DB",
+		"func SetLog(val io.Writer, opts ...reg.Option) error",
+		"func ValidateEntries(opts ...reg.Option) error",
+		"func Validate(opts ...reg.Option) error {\n\treturn ValidateEntries(opts...)\n}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code does not contain %q:\n%s", want, src)
+		}
+	}
+
+	if strings.Contains(string(src), "LogKey.Get(opts...); err != nil") {
+		t.Errorf("optional entry is validated:\n%s", src)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "entries_reg.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the previous output is ignored when generating again
+	if again, err := generate(dir, "Entries", "entries_reg.go", "Validate"); err != nil || string(again) != string(src) {
+		t.Fatalf("regenerate = %v, output changed %v", err, string(again) != string(src))
+	}
+
+	cmd := exec.Command("go", "run", "./cmd")
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run error = %v\n%s", err, out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 4 ||
+		!strings.HasPrefix(lines[0], "UserStore: ") ||
+		!strings.HasPrefix(lines[1], "PrimaryDB: ") ||
+		!strings.HasPrefix(lines[2], "Name: ") ||
+		lines[3] != "pg *store.DB(primary)" {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	src := `package app
+
+type Embedded struct{ error }
+
+type NotStruct int
+`
+	if err := os.WriteFile(filepath.Join(dir, "decl.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for typeName, want := range map[string]string{
+		"Embedded":  "embedded fields are not supported",
+		"NotStruct": "is not a struct",
+		"Missing":   "not found",
+	} {
+		if _, err := generate(dir, typeName, "", "Validate"); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("generate(%s) err = %v; want it to contain %q", typeName, err, want)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/mp3cko/registry/access"
+)
+
+// registryPkg is the import path of the registry package
+const registryPkg = "github.com/mp3cko/registry"
+
+// opKind groups registry functions by the options they accept
+type opKind int
+
+const (
+	opConstructor opKind = iota
+	opSet
+	opGet
+	opGetMatching
+	opGetAll
+	opUnset
+	opHealth
+)
+
+func (t opKind) String() string {
+	return [...]string{"NewRegistry", "Set", "Get", "GetMatching", "GetAll", "Unset", "Health"}[t]
+}
+
+// ops maps registry functions to their kind
+var ops = map[string]opKind{
+	"NewRegistry":             opConstructor,
+	"Set":                     opSet,
+	"MustSet":                 opSet,
+	"SetAs":                   opSet,
+	"Swap":                    opSet,
+	"CompareAndSwap":          opSet,
+	"Update":                  opSet,
+	"GetOrSet":                opSet,
+	"GetOrCreate":             opSet,
+	"Provide":                 opSet,
+	"BindConfig":              opSet,
+	"Append":                  opSet,
+	"Put":                     opSet,
+	"Get":                     opGet,
+	"MustGet":                 opGet,
+	"GetMatching":             opGetMatching,
+	"GetAllOf":                opGetMatching,
+	"GetSlice":                opGet,
+	"GetMap":                  opGet,
+	"GetAll":                  opGetAll,
+	"MustGetAll":              opGetAll,
+	"Entries":                 opGetAll,
+	"Unset":                   opUnset,
+	"MustUnset":               opUnset,
+	"UnsetNamespace":          opUnset,
+	"Remove":                  opUnset,
+	"Delete":                  opUnset,
+	"Health":                  opHealth,
+	"HealthHandler":           opHealth,
+	"HealthHandlerWithErrors": opHealth,
+}
+
+// collectionOps are the ops on multi-bindings, only their options are checked
+var collectionOps = map[string]bool{"Append": true, "Put": true, "GetSlice": true, "GetMap": true, "Remove": true, "Delete": true}
+
+// outsideConstructor are the kinds of every op except NewRegistry
+var outsideConstructor = []opKind{opSet, opGet, opGetMatching, opGetAll, opUnset, opHealth}
+
+// unsupported lists the op kinds each option is invalid for, mirroring the options validity matrix
+var unsupported = map[string][]opKind{
+	"WithRegistry":       {opConstructor},
+	"WithCallerPackage":  {opConstructor},
+	"WithCallerSkip":     {opConstructor},
+	"WithName":           {opGetMatching},
+	"WithProfile":        {opConstructor, opGetMatching, opGetAll, opHealth},
+	"WithAlias":          {opConstructor, opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithCondition":      {opConstructor, opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithPrimary":        {opConstructor, opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithPriority":       {opConstructor, opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithCheckTimeout":   {opConstructor, opSet, opGet, opGetMatching, opGetAll, opUnset},
+	"WithNonNil":         {opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithNamePattern":    {opSet, opGet, opUnset},
+	"WithUniqueName":     {opGet, opGetMatching, opGetAll, opUnset, opHealth},
+	"WithAccessibility":  {opGet, opGetMatching, opUnset},
+	"WithNamedness":      {opGet, opGetMatching, opUnset},
+	"WithValidator":      outsideConstructor,
+	"WithPolicy":         outsideConstructor,
+	"WithActiveProfiles": outsideConstructor,
+	"WithMetrics":        outsideConstructor,
+	"WithLogger":         outsideConstructor,
+	"WithLogLevel":       outsideConstructor,
+	"WithCloneConfig":    outsideConstructor,
+	"WithCloneEntries":   outsideConstructor,
+	"WithCloneRegistry":  outsideConstructor,
+}
+
+// diagnostic is a finding pointing at a call site
+type diagnostic struct {
+	pos token.Position
+	msg string
+}
+
+func (t diagnostic) String() string {
+	return fmt.Sprintf("%s: %s", t.pos, t.msg)
+}
+
+// lookup is a Get of a type, reported if the type is never registered
+type lookup struct {
+	pos token.Position
+	op  string
+	key string
+}
+
+// checker collects diagnostics across all the analyzed packages
+type checker struct {
+	fset        *token.FileSet
+	diagnostics []diagnostic
+	registered  map[string]bool // keys of registered types
+	generic     bool            // a type parameter was registered, registered types are not known statically
+	lookups     []lookup
+}
+
+func newChecker(fset *token.FileSet) *checker {
+	return &checker{fset: fset, registered: make(map[string]bool)}
+}
+
+func (t *checker) report(pos token.Pos, format string, args ...any) {
+	t.diagnostics = append(t.diagnostics, diagnostic{t.fset.Position(pos), fmt.Sprintf(format, args...)})
+}
+
+// checkPackage checks every registry call of the package
+func (t *checker) checkPackage(pkg *typedPackage) {
+	// the registry is allowed to call itself however it wants
+	if pkg.path == registryPkg {
+		return
+	}
+
+	for _, f := range pkg.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				t.checkCall(pkg, call)
+			}
+
+			return true
+		})
+	}
+}
+
+// checkCall checks a single call, ignoring calls of anything but registry ops
+func (t *checker) checkCall(pkg *typedPackage, call *ast.CallExpr) {
+	ident := calleeIdent(call.Fun)
+	if ident == nil {
+		return
+	}
+
+	fn, ok := pkg.info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != registryPkg {
+		return
+	}
+
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t.checkKeyCall(call, fn, recv.Type())
+		return
+	}
+
+	kind, ok := ops[fn.Name()]
+	if !ok {
+		return
+	}
+
+	opts := t.options(pkg, call, fn)
+	for _, opt := range opts {
+		for _, k := range unsupported[opt.name] {
+			if k == kind {
+				t.report(opt.pos, "%s does not support %s", fn.Name(), opt.name)
+			}
+		}
+	}
+
+	// collections are separate from instances, appending doesn't register T and an empty collection is not an error
+	if collectionOps[fn.Name()] {
+		return
+	}
+
+	var typeArgs *types.TypeList
+	if inst, ok := pkg.info.Instances[ident]; ok {
+		typeArgs = inst.TypeArgs
+	}
+
+	if typeArgs == nil || typeArgs.Len() == 0 {
+		return
+	}
+
+	key := typeArgs.At(0)
+
+	switch {
+	case kind == opSet:
+		t.register(key)
+		t.checkAccess(pkg, call, fn.Name(), key, opts)
+
+		// SetAs registers under additional reflect.TypeFor[X]() types
+		if fn.Name() == "SetAs" && len(call.Args) > 1 {
+			for _, rt := range typeForArgs(pkg, call.Args[1]) {
+				t.register(rt)
+				t.checkAccess(pkg, call, fn.Name(), rt, opts)
+			}
+		}
+	case kind == opGet || kind == opGetMatching:
+		if _, isParam := key.(*types.TypeParam); !isParam {
+			t.lookups = append(t.lookups, lookup{t.fset.Position(call.Pos()), fn.Name(), types.TypeString(key, nil)})
+		}
+	}
+}
+
+// checkKeyCall records registrations and lookups made using Key[T] methods
+func (t *checker) checkKeyCall(call *ast.CallExpr, fn *types.Func, recv types.Type) {
+	key, ok := types.Unalias(recv).(*types.Named)
+	if !ok || key.Obj().Name() != "Key" || key.TypeArgs().Len() != 1 {
+		return
+	}
+
+	rt := key.TypeArgs().At(0)
+
+	switch fn.Name() {
+	case "Set":
+		t.register(rt)
+	case "Get", "MustGet":
+		if _, isParam := rt.(*types.TypeParam); !isParam {
+			t.lookups = append(t.lookups, lookup{t.fset.Position(call.Pos()), "Key." + fn.Name(), types.TypeString(rt, nil)})
+		}
+	}
+}
+
+// register records a registered type
+func (t *checker) register(rt types.Type) {
+	if _, isParam := rt.(*types.TypeParam); isParam {
+		t.generic = true
+		return
+	}
+
+	t.registered[types.TypeString(rt, nil)] = true
+}
+
+// finish reports lookups of types never registered and returns all the diagnostics sorted by position
+func (t *checker) finish() []diagnostic {
+	if !t.generic {
+		for _, l := range t.lookups {
+			if !t.registered[l.key] {
+				t.diagnostics = append(t.diagnostics, diagnostic{l.pos, fmt.Sprintf("%s[%s] but %s is never registered", l.op, l.key, l.key)})
+			}
+		}
+	}
+
+	sort.Slice(t.diagnostics, func(i, j int) bool {
+		a, b := t.diagnostics[i].pos, t.diagnostics[j].pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+
+	return t.diagnostics
+}
+
+// optionUse is an option passed to a registry op
+type optionUse struct {
+	name string
+	pos  token.Pos
+	arg  ast.Expr // first argument of the option, if any
+}
+
+// options returns the options passed to the registry op, options passed using opts... are not known statically
+func (t *checker) options(pkg *typedPackage, call *ast.CallExpr, fn *types.Func) []optionUse {
+	sig := fn.Type().(*types.Signature)
+	if !sig.Variadic() || call.Ellipsis.IsValid() {
+		return nil
+	}
+
+	var opts []optionUse
+	for _, arg := range call.Args[min(sig.Params().Len()-1, len(call.Args)):] {
+		opts = append(opts, optionChain(pkg, arg)...)
+	}
+
+	return opts
+}
+
+// optionChain returns the options of a single option expression, ex. WithName("a").WithUniqueType()
+func optionChain(pkg *typedPackage, expr ast.Expr) []optionUse {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	ident := calleeIdent(call.Fun)
+	if ident == nil {
+		return nil
+	}
+
+	fn, ok := pkg.info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != registryPkg || !strings.HasPrefix(fn.Name(), "With") {
+		return nil
+	}
+
+	use := optionUse{name: fn.Name(), pos: ident.Pos()}
+	if len(call.Args) > 0 {
+		use.arg = call.Args[0]
+	}
+
+	// builder methods chain onto the options before them
+	var opts []optionUse
+	if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && fn.Type().(*types.Signature).Recv() != nil {
+		opts = optionChain(pkg, sel.X)
+	}
+
+	return append(opts, use)
+}
+
+// calleeIdent returns the identifier of the called function, ex. Get for reg.Get[T]
+func calleeIdent(fun ast.Expr) *ast.Ident {
+	switch f := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		return f
+	case *ast.SelectorExpr:
+		return f.Sel
+	case *ast.IndexExpr:
+		return calleeIdent(f.X)
+	case *ast.IndexListExpr:
+		return calleeIdent(f.X)
+	default:
+		return nil
+	}
+}
+
+// typeForArgs returns the types of reflect.TypeFor[X]() calls in a composite literal
+func typeForArgs(pkg *typedPackage, expr ast.Expr) []types.Type {
+	lit, ok := ast.Unparen(expr).(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+
+	var rts []types.Type
+	for _, elt := range lit.Elts {
+		call, ok := ast.Unparen(elt).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+
+		ident := calleeIdent(call.Fun)
+		if ident == nil {
+			continue
+		}
+
+		fn, ok := pkg.info.Uses[ident].(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" || fn.Name() != "TypeFor" {
+			continue
+		}
+
+		if inst, ok := pkg.info.Instances[ident]; ok {
+			rts = append(rts, inst.TypeArgs.At(0))
+		}
+	}
+
+	return rts
+}
+
+// checkAccess reports registered types the registry would reject because of their accessibility or namedness
+func (t *checker) checkAccess(pkg *typedPackage, call *ast.CallExpr, op string, rt types.Type, opts []optionUse) {
+	if _, isParam := rt.(*types.TypeParam); isParam {
+		return
+	}
+
+	caller := pkg.path
+	requiredAccessibility := access.Accessibility(access.AccessibleInsidePackage)
+	requiredNamedness := access.NamednessUndefined
+
+	for _, opt := range opts {
+		switch opt.name {
+		case "WithCallerPackage":
+			if s, ok := constValue(pkg, opt.arg); ok && s.Kind() == constant.String {
+				caller = constant.StringVal(s)
+			}
+		case "WithAccessibility":
+			if v, ok := constValue(pkg, opt.arg); ok {
+				if n, exact := constant.Int64Val(v); exact {
+					requiredAccessibility = max(requiredAccessibility, access.Accessibility(n))
+				}
+			}
+		case "WithNamedness":
+			if v, ok := constValue(pkg, opt.arg); ok {
+				if n, exact := constant.Int64Val(v); exact {
+					requiredNamedness = access.Namedness(n)
+				}
+			}
+		}
+	}
+
+	if level := accessibility(rt, caller); level < requiredAccessibility {
+		t.report(call.Pos(), "%s of %s fails: accessibility too low. Wanted at least '%s' but got '%s': %s", op, types.TypeString(rt, nil), requiredAccessibility, level, strings.Join(culprits(rt, caller, requiredAccessibility), "; "))
+	}
+
+	if n := namedness(rt); n < requiredNamedness {
+		t.report(call.Pos(), "%s of %s fails: namedness too low. Wanted at least '%s' but got '%s'", op, types.TypeString(rt, nil), requiredNamedness, n)
+	}
+}
+
+// constValue returns the constant value of an expression
+func constValue(pkg *typedPackage, expr ast.Expr) (constant.Value, bool) {
+	if expr == nil {
+		return nil, false
+	}
+
+	tv, ok := pkg.info.Types[expr]
+	if !ok || tv.Value == nil {
+		return nil, false
+	}
+
+	return tv.Value, true
+}
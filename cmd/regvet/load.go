@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// listedPackage is the subset of `go list -json` output needed to type check a package
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	CgoFiles   []string
+	Export     string
+	DepOnly    bool
+	ImportMap  map[string]string
+	Error      *struct{ Err string }
+}
+
+// typedPackage is a parsed and type checked package
+type typedPackage struct {
+	path  string
+	files []*ast.File
+	info  *types.Info
+}
+
+// load lists the packages matching the patterns from dir and type checks them from source, dependencies are imported from export data
+func load(dir string, fset *token.FileSet, patterns ...string) ([]*typedPackage, error) {
+	args := append([]string{"list", "-e", "-export", "-deps", "-json=ImportPath,Dir,GoFiles,CgoFiles,Export,DepOnly,ImportMap,Error"}, patterns...)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w: %s", err, stderr.Bytes())
+	}
+
+	var (
+		targets []*listedPackage
+		exports = make(map[string]string)
+	)
+
+	for dec := json.NewDecoder(bytes.NewReader(out)); ; {
+		p := new(listedPackage)
+		if err := dec.Decode(p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("decoding go list output: %w", err)
+		}
+
+		if p.Error != nil {
+			return nil, fmt.Errorf("package %s: %s", p.ImportPath, p.Error.Err)
+		}
+
+		exports[p.ImportPath] = p.Export
+		if !p.DepOnly {
+			targets = append(targets, p)
+		}
+	}
+
+	imp := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		export, ok := exports[path]
+		if !ok || export == "" {
+			return nil, fmt.Errorf("no export data for %q", path)
+		}
+
+		return os.Open(export)
+	})
+
+	pkgs := make([]*typedPackage, 0, len(targets))
+	for _, p := range targets {
+		if len(p.CgoFiles) > 0 {
+			return nil, fmt.Errorf("package %s: cgo packages are not supported", p.ImportPath)
+		}
+
+		pkg, err := check(fset, imp, p)
+		if err != nil {
+			return nil, err
+		}
+
+		pkgs = append(pkgs, pkg)
+	}
+
+	return pkgs, nil
+}
+
+// check parses and type checks a listed package
+func check(fset *token.FileSet, imp types.Importer, p *listedPackage) (*typedPackage, error) {
+	files := make([]*ast.File, 0, len(p.GoFiles))
+	for _, name := range p.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(p.Dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	info := &types.Info{
+		Types:     make(map[ast.Expr]types.TypeAndValue),
+		Uses:      make(map[*ast.Ident]types.Object),
+		Instances: make(map[*ast.Ident]types.Instance),
+	}
+
+	conf := types.Config{Importer: mappedImporter{imp, p.ImportMap}}
+	if _, err := conf.Check(p.ImportPath, fset, files, info); err != nil {
+		return nil, fmt.Errorf("type checking %s: %w", p.ImportPath, err)
+	}
+
+	return &typedPackage{path: p.ImportPath, files: files, info: info}, nil
+}
+
+// mappedImporter resolves import paths through the package's import map (ex. for vendored packages)
+type mappedImporter struct {
+	types.Importer
+	importMap map[string]string
+}
+
+func (t mappedImporter) Import(path string) (*types.Package, error) {
+	if mapped, ok := t.importMap[path]; ok {
+		path = mapped
+	}
+
+	return t.Importer.Import(path)
+}
@@ -0,0 +1,74 @@
+// Command regvet reports statically detectable misuse of the registry:
+//
+//   - options passed to ops that don't support them, ex. WithUniqueName passed to Get, GetAll or Unset,
+//     WithRegistry passed to NewRegistry or clone options used outside NewRegistry
+//   - Set (and other registering ops) of types the registry would reject because of their accessibility or namedness
+//   - Get of types never registered in any of the analyzed packages
+//
+// Options passed as opts... and registries configured at runtime are not known statically, so regvet only checks what it can see.
+// Registering a type parameter (ex. inside a generic wrapper) disables the never registered check.
+//
+// Usage, from the root of the module:
+//
+//	regvet [packages]  // defaults to ./...
+//
+// Diagnostics are printed as file:line:col: message, the exit code is 1 if any were found and 2 if the packages couldn't be loaded.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: regvet [packages]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	os.Exit(run(".", os.Stdout, os.Stderr, flag.Args()...))
+}
+
+// run checks the packages matching the patterns from dir, writing diagnostics to out and returns the exit code
+func run(dir string, out, errOut io.Writer, patterns ...string) int {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	diagnostics, err := vet(dir, patterns...)
+	if err != nil {
+		fmt.Fprintln(errOut, "regvet:", err)
+		return 2
+	}
+
+	for _, d := range diagnostics {
+		fmt.Fprintln(out, d)
+	}
+
+	if len(diagnostics) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// vet loads and checks the packages matching the patterns from dir
+func vet(dir string, patterns ...string) ([]diagnostic, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := load(dir, fset, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newChecker(fset)
+	for _, pkg := range pkgs {
+		c.checkPackage(pkg)
+	}
+
+	return c.finish(), nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testModule is a module using the registry, lines expecting a diagnostic are marked with a // want "substring" comment
+var testModule = map[string]string{
+	"go.mod": `module example.com/app
+
+go 1.24
+
+require github.com/mp3cko/registry v0.0.0
+
+replace github.com/mp3cko/registry => REGISTRY
+`,
+	"internal/secret/secret.go": `package secret
+
+type Key struct{}
+`,
+	"other/other.go": `package other
+
+type hidden struct{}
+
+func New() hidden { return hidden{} }
+`,
+	"app.go": `package app
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	reg "github.com/mp3cko/registry"
+	"github.com/mp3cko/registry/access"
+
+	"example.com/app/internal/secret"
+	"example.com/app/other"
+)
+
+type Service interface{ Run() }
+
+type Runner interface{ Run() }
+
+type impl struct{}
+
+type Config struct{ Addr string }
+
+func (impl) Run() {}
+
+func wire() {
+	r, _ := reg.NewRegistry(reg.WithRegistry(nil)) // want "NewRegistry does not support WithRegistry"
+	_ = reg.Set[Service](impl{}, reg.WithRegistry(r))
+	_, _ = reg.Get[Service](reg.WithUniqueName())          // want "Get does not support WithUniqueName"
+	_, _ = reg.GetAll(reg.WithName("a").WithUniqueName())  // want "GetAll does not support WithUniqueName"
+	_ = reg.Unset(impl{}, reg.WithCloneConfig(r))          // want "Unset does not support WithCloneConfig"
+	_ = reg.Set(other.New())                               // want "other.hidden (unexported)"
+	_ = reg.Set(secret.Key{})
+	_ = reg.Set(secret.Key{}, reg.WithAccessibility(access.AccessibleEverywhere)) // want "secret.Key (internal)"
+	_ = reg.Set("addr", reg.WithNamedness(access.DefinedType))                   // want "namedness too low"
+	_ = reg.SetAs(impl{}, []reflect.Type{reflect.TypeFor[Runner]()}, reg.WithName("runner"))
+	_, _ = reg.Get[Runner]()
+	_, _ = reg.Get[*impl]() // want "Get[*example.com/app.impl] but *example.com/app.impl is never registered"
+
+	// keys register and look up their type
+	_ = reg.NewKey[*secret.Key]("a").Set(&secret.Key{})
+	_, _ = reg.Get[*secret.Key](reg.WithName("a"))
+	_, _ = reg.NewKey[[]impl]("").Get() // want "Key.Get[[]example.com/app.impl] but []example.com/app.impl is never registered"
+
+	// pattern lookups narrow their result using WithNamePattern, not WithName
+	_, _ = reg.GetAllOf[Service](reg.WithNamePattern(reg.Glob("db/*")))
+	_, _ = reg.GetMatching[Service](reg.Glob("*"), reg.WithNamePattern(reg.Glob("db/*")))
+	_, _ = reg.GetAllOf[Service](reg.WithName("db")) // want "GetAllOf does not support WithName"
+
+	// collections are not instances, removing from them doesn't need a registration
+	_ = reg.Remove[Service](impl{})
+	_ = reg.Delete[string, Service]("a", reg.WithAlias("b")) // want "Delete does not support WithAlias"
+
+	// check timeouts only apply to health checks
+	_ = reg.Health(context.Background(), reg.WithCheckTimeout(time.Second))
+	_ = reg.HealthHandler(reg.WithNamespace("live").WithCheckTimeout(time.Second))
+	_, _ = reg.GetAll(reg.WithCheckTimeout(time.Second))  // want "GetAll does not support WithCheckTimeout"
+	_ = reg.HealthHandlerWithErrors(reg.WithUniqueName()) // want "HealthHandlerWithErrors does not support WithUniqueName"
+
+	// config bindings register their type
+	_, _ = reg.BindConfig[Config]([]reg.Source{reg.Env("APP_")}, reg.WithName("app"))
+	_, _ = reg.Get[Config](reg.WithName("app"))
+	_, _ = reg.BindConfig[Config](nil, reg.WithUniqueName().WithNamePattern(reg.Glob("*"))) // want "BindConfig does not support WithNamePattern"
+
+	// options passed as opts... are not known statically
+	opts := []reg.Option{reg.WithUniqueName()}
+	_, _ = reg.Get[Service](opts...)
+}
+`,
+}
+
+var wantRe = regexp.MustCompile(`// want "(.*)"`)
+
+func TestVet(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	want := make(map[string]string) // "file:line" -> expected substring
+
+	for name, src := range testModule {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		src = strings.ReplaceAll(src, "REGISTRY", root)
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, line := range strings.Split(src, "\n") {
+			if m := wantRe.FindStringSubmatch(line); m != nil {
+				want[name+":"+strconv.Itoa(i+1)] = m[1]
+			}
+		}
+	}
+
+	diagnostics, err := vet(dir, "./...")
+	if err != nil {
+		t.Fatalf("vet error = %v", err)
+	}
+
+	for _, d := range diagnostics {
+		rel, _ := filepath.Rel(dir, d.pos.Filename)
+		at := rel + ":" + strconv.Itoa(d.pos.Line)
+
+		substr, ok := want[at]
+		if !ok {
+			t.Errorf("unexpected diagnostic %s", d)
+			continue
+		}
+
+		if !strings.Contains(d.msg, substr) {
+			t.Errorf("diagnostic at %s = %q; want it to contain %q", at, d.msg, substr)
+		}
+
+		delete(want, at)
+	}
+
+	for at, substr := range want {
+		t.Errorf("missing diagnostic at %s containing %q", at, substr)
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	if code := run("../..", &out, &errOut, "./..."); code != 0 {
+		t.Fatalf("run on the registry module = %d; want 0, output %s%s", code, out.String(), errOut.String())
+	}
+
+	if code := run(t.TempDir(), &out, &errOut, "./..."); code != 2 {
+		t.Fatalf("run outside a module = %d; want 2", code)
+	}
+}
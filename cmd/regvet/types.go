@@ -0,0 +1,128 @@
+package main
+
+import (
+	"go/types"
+
+	"github.com/mp3cko/registry/access"
+)
+
+// accessibility is the go/types variant of access.InfoFrom, returning the accessibility of rt as seen from the package caller
+func accessibility(rt types.Type, caller string) access.Accessibility {
+	switch {
+	case len(culprits(rt, "", access.AccessibleEverywhere)) == 0:
+		return access.AccessibleEverywhere
+	case len(culprits(rt, caller, access.AccessibleInsideInternalTree)) == 0:
+		return access.AccessibleInsideInternalTree
+	case len(culprits(rt, caller, access.AccessibleInsidePackage)) == 0:
+		return access.AccessibleInsidePackage
+	default:
+		return access.NotAccessible
+	}
+}
+
+// culprits returns the named types inside rt preventing it from having at least the accessibility level as seen from the package caller
+func culprits(rt types.Type, caller string, level access.Accessibility) []string {
+	var found []string
+
+	walkNamed(rt, make(map[types.Type]bool), func(obj *types.TypeName) {
+		// predeclared types are accessible
+		if obj.Pkg() == nil {
+			return
+		}
+
+		pkg := obj.Pkg().Path()
+
+		switch {
+		case level <= access.AccessibleInsidePackage && pkg == caller:
+		case !access.IsExported(obj.Name()):
+			found = append(found, obj.Pkg().Name()+"."+obj.Name()+" (unexported)")
+		case !access.Importable(pkg, caller) || level == access.AccessibleEverywhere && !access.Importable(pkg, ""):
+			found = append(found, obj.Pkg().Name()+"."+obj.Name()+" (internal)")
+		}
+	})
+
+	return found
+}
+
+// walkNamed calls visit for every named type referenced by rt, including type arguments of generic instantiations
+func walkNamed(rt types.Type, seen map[types.Type]bool, visit func(*types.TypeName)) {
+	if rt == nil || seen[rt] {
+		return
+	}
+
+	// avoid infinite recursion
+	seen[rt] = true
+
+	switch t := rt.(type) {
+	case *types.Alias:
+		walkNamed(types.Unalias(t), seen, visit)
+	case *types.Named:
+		visit(t.Obj())
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			walkNamed(t.TypeArgs().At(i), seen, visit)
+		}
+	case *types.Pointer:
+		walkNamed(t.Elem(), seen, visit)
+	case *types.Slice:
+		walkNamed(t.Elem(), seen, visit)
+	case *types.Array:
+		walkNamed(t.Elem(), seen, visit)
+	case *types.Chan:
+		walkNamed(t.Elem(), seen, visit)
+	case *types.Map:
+		walkNamed(t.Key(), seen, visit)
+		walkNamed(t.Elem(), seen, visit)
+	case *types.Signature:
+		walkNamed(t.Params(), seen, visit)
+		walkNamed(t.Results(), seen, visit)
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			walkNamed(t.At(i).Type(), seen, visit)
+		}
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			walkNamed(t.Field(i).Type(), seen, visit)
+		}
+	case *types.Interface:
+		for i := 0; i < t.NumMethods(); i++ {
+			walkNamed(t.Method(i).Type(), seen, visit)
+		}
+	}
+}
+
+// namedness is the go/types variant of access.Info namedness, refined to DefinedType for types defined in a package as the registry does when checking requirements
+func namedness(rt types.Type) access.Namedness {
+	rt = types.Unalias(rt)
+	for {
+		ptr, ok := rt.(*types.Pointer)
+		if !ok {
+			break
+		}
+		rt = types.Unalias(ptr.Elem())
+	}
+
+	switch t := rt.(type) {
+	case *types.Basic:
+		return access.NamedType
+	case *types.Named:
+		// generic instantiations are only named if all their type arguments are
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			arg := t.TypeArgs().At(i)
+			if iface, ok := types.Unalias(arg).(*types.Interface); ok && iface.Empty() {
+				continue
+			}
+
+			if namedness(arg) == access.AnonymousType {
+				return access.AnonymousType
+			}
+		}
+
+		if t.Obj().Pkg() == nil {
+			return access.NamedType
+		}
+
+		return access.DefinedType
+	default:
+		return access.AnonymousType
+	}
+}
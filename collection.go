@@ -0,0 +1,343 @@
+package reg
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// collection holds the elements of a multi-binding, registered using Append or Put
+type collection struct {
+	items []any       // elements in order of appending
+	keyed map[any]any // elements by key
+}
+
+// len returns the number of elements
+func (t *collection) len() int {
+	return len(t.items) + len(t.keyed)
+}
+
+// merge adds the elements of src
+func (t *collection) merge(src *collection) {
+	t.items = append(t.items, src.items...)
+
+	if len(src.keyed) > 0 && t.keyed == nil {
+		t.keyed = make(map[any]any, len(src.keyed))
+	}
+
+	maps.Copy(t.keyed, src.keyed)
+}
+
+// clone returns a copy of the collection
+func (t *collection) clone() *collection {
+	return &collection{items: slices.Clone(t.items), keyed: maps.Clone(t.keyed)}
+}
+
+// Append adds val to the ordered collection of T, so independent packages can contribute to it (ex. middlewares) without knowing about each other.
+//
+//	Append[Middleware](logging)
+//	Append[Middleware](auth)
+//	mws, err := GetSlice[Middleware]() // [logging auth]
+//
+// Collections are separate from instances, Get[T] doesn't see them. [WithName] and [WithNamespace] select the collection,
+// values are checked like instances registered using [Set] (accessibility, namedness, validators).
+func Append[T any](val T, opts ...Option) error {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpAppend, reflect.SliceOf(rt), opts)
+	if err != nil {
+		return err
+	}
+
+	defer r.cleanup()
+
+	key := entryKey{reflect.SliceOf(rt), r.entryName()}
+
+	err = appendItem(r, key, rt, val)
+	r.observe(OpAppend, key.rt, err)
+
+	return err
+}
+
+// GetSlice retrieves the collection of T built using [Append], in order of appending. If nothing was appended the returned slice is empty.
+func GetSlice[T any](opts ...Option) ([]T, error) {
+	rt := reflect.TypeFor[[]T]()
+
+	r, err := acquire(OpGetSlice, rt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkCollectionOpts(OpGetSlice, r.callOptions); err != nil {
+		r.observe(OpGetSlice, rt, err)
+		return nil, err
+	}
+
+	c := r.collections[entryKey{rt, r.entryName()}]
+	if c == nil {
+		return []T{}, nil
+	}
+
+	out := make([]T, len(c.items))
+	for i, item := range c.items {
+		out[i], _ = item.(T)
+	}
+
+	return out, nil
+}
+
+// Remove removes every element equal to val from the collection of T built using [Append], returning ErrNotFound if there is none.
+// val must be comparable, otherwise ErrNotSupported is returned. [WithName] and [WithNamespace] select the collection.
+func Remove[T any](val T, opts ...Option) error {
+	rt := reflect.TypeFor[[]T]()
+
+	r, err := acquire(OpRemove, rt, opts)
+	if err != nil {
+		return err
+	}
+
+	defer r.cleanup()
+
+	err = removeItem(r, entryKey{rt, r.entryName()}, val)
+	r.observe(OpRemove, rt, err)
+
+	return err
+}
+
+// Put adds val under key to the keyed collection of V, so independent packages can contribute to it (ex. commands by name).
+//
+//	Put[string, Command]("serve", serveCmd)
+//	cmds, err := GetMap[string, Command]() // map[serve:serveCmd]
+//
+// Putting an existing key replaces its value, unless the registry or the call enforces unique names (see [WithUniqueName]) in which case
+// ErrNotUniqueName is returned. Otherwise it behaves like [Append], only the values are checked (the keys are not).
+func Put[K comparable, V any](key K, val V, opts ...Option) error {
+	r, err := acquire(OpPut, reflect.TypeFor[map[K]V](), opts)
+	if err != nil {
+		return err
+	}
+
+	defer r.cleanup()
+
+	ck := entryKey{reflect.TypeFor[map[K]V](), r.entryName()}
+
+	err = putItem(r, ck, reflect.TypeFor[V](), key, val)
+	r.observe(OpPut, ck.rt, err)
+
+	return err
+}
+
+// GetMap retrieves a copy of the keyed collection of V built using [Put]. If nothing was put the returned map is empty.
+func GetMap[K comparable, V any](opts ...Option) (map[K]V, error) {
+	rt := reflect.TypeFor[map[K]V]()
+
+	r, err := acquire(OpGetMap, rt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkCollectionOpts(OpGetMap, r.callOptions); err != nil {
+		r.observe(OpGetMap, rt, err)
+		return nil, err
+	}
+
+	c := r.collections[entryKey{rt, r.entryName()}]
+	if c == nil {
+		return map[K]V{}, nil
+	}
+
+	out := make(map[K]V, len(c.keyed))
+	for k, v := range c.keyed {
+		out[k.(K)], _ = v.(V)
+	}
+
+	return out, nil
+}
+
+// Delete removes key from the keyed collection of V built using [Put], returning ErrNotFound if it isn't there.
+// [WithName] and [WithNamespace] select the collection.
+func Delete[K comparable, V any](key K, opts ...Option) error {
+	rt := reflect.TypeFor[map[K]V]()
+
+	r, err := acquire(OpDelete, rt, opts)
+	if err != nil {
+		return err
+	}
+
+	defer r.cleanup()
+
+	err = deleteItem(r, entryKey{rt, r.entryName()}, key)
+	r.observe(OpDelete, rt, err)
+
+	return err
+}
+
+// appendItem appends val to the collection stored under key, caller must handle mutex locking
+func appendItem(r *registry, key entryKey, rt reflect.Type, val any) error {
+	if err := checkCollectionOpts(OpAppend, r.callOptions); err != nil {
+		return err
+	}
+
+	if err := checkType(r, rt); err != nil {
+		return err
+	}
+
+	if err := validateValue(r, []reflect.Type{rt}, val); err != nil {
+		return err
+	}
+
+	c := r.collectionFor(key)
+	c.items = append(c.items, val)
+	r.version++
+
+	return nil
+}
+
+// putItem puts val under k into the collection stored under key, caller must handle mutex locking
+func putItem(r *registry, key entryKey, vt reflect.Type, k, val any) error {
+	if err := checkCollectionOpts(OpPut, r.callOptions); err != nil {
+		return err
+	}
+
+	// keys only select elements, the policies and accessibility apply to the values
+	if err := checkType(r, vt); err != nil {
+		return err
+	}
+
+	if err := validateValue(r, []reflect.Type{vt}, val); err != nil {
+		return err
+	}
+
+	c := r.collections[key]
+	if _, ok := c.get(k); ok && (r.config.uniqueNames || r.callOptions.uniqueName) {
+		return fmt.Errorf("Put '%s' key '%v' failed: %w", key.rt, k, ErrNotUniqueName)
+	}
+
+	c = r.collectionFor(key)
+	if c.keyed == nil {
+		c.keyed = map[any]any{}
+	}
+
+	c.keyed[k] = val
+	r.version++
+
+	return nil
+}
+
+// removeItem removes the elements equal to val from the collection stored under key, caller must handle mutex locking
+func removeItem(r *registry, key entryKey, val any) error {
+	if err := checkCollectionOpts(OpRemove, r.callOptions); err != nil {
+		return err
+	}
+
+	// a nil interface is comparable, it equals the nil elements
+	if val != nil && !reflect.ValueOf(val).Comparable() {
+		return fmt.Errorf("Remove '%s' failed: %w, '%T' is not comparable", key.rt, ErrNotSupported, val)
+	}
+
+	c := r.collections[key]
+	if c == nil {
+		return fmt.Errorf("Remove '%s' failed: %w", key.rt, ErrNotFound)
+	}
+
+	n := len(c.items)
+	c.items = slices.DeleteFunc(c.items, func(item any) bool {
+		return (item == nil || reflect.ValueOf(item).Comparable()) && item == val
+	})
+
+	if len(c.items) == n {
+		return fmt.Errorf("Remove '%s' failed: %w", key.rt, ErrNotFound)
+	}
+
+	r.pruneCollection(key)
+	r.version++
+
+	return nil
+}
+
+// deleteItem removes k from the collection stored under key, caller must handle mutex locking
+func deleteItem(r *registry, key entryKey, k any) error {
+	if err := checkCollectionOpts(OpDelete, r.callOptions); err != nil {
+		return err
+	}
+
+	c := r.collections[key]
+	if _, ok := c.get(k); !ok {
+		return fmt.Errorf("Delete '%s' key '%v' failed: %w", key.rt, k, ErrNotFound)
+	}
+
+	delete(c.keyed, k)
+	r.pruneCollection(key)
+	r.version++
+
+	return nil
+}
+
+// pruneCollection drops the collection stored under key once it is empty
+func (t *registry) pruneCollection(key entryKey) {
+	if c := t.collections[key]; c != nil && c.len() == 0 {
+		delete(t.collections, key)
+	}
+}
+
+// get returns the value stored under k, the collection may be nil
+func (t *collection) get(k any) (any, bool) {
+	if t == nil {
+		return nil, false
+	}
+
+	val, ok := t.keyed[k]
+
+	return val, ok
+}
+
+// collectionFor returns the collection stored under key, creating it if needed
+func (t *registry) collectionFor(key entryKey) *collection {
+	if t.collections == nil {
+		t.collections = map[entryKey]*collection{}
+	}
+
+	c, ok := t.collections[key]
+	if !ok {
+		c = new(collection)
+		t.collections[key] = c
+	}
+
+	return c
+}
+
+// cloneCollections returns a deep copy of the collections
+func (t *registry) cloneCollections() map[entryKey]*collection {
+	if t.collections == nil {
+		return nil
+	}
+
+	clone := make(map[entryKey]*collection, len(t.collections))
+	for key, c := range t.collections {
+		clone[key] = c.clone()
+	}
+
+	return clone
+}
+
+// checkCollectionOpts returns an error if the call options are not supported by the collection op
+func checkCollectionOpts(op Op, co *callOptions) error {
+	if co.uniqueName && op != OpPut {
+		return fmt.Errorf("%s WithUniqueName: %w", op, ErrNotSupported)
+	}
+
+	if co.nonNil && op != OpAppend && op != OpPut {
+		return fmt.Errorf("%s WithNonNil: %w", op, ErrNotSupported)
+	}
+
+	if co.uniqueType || co.namePattern != nil || co.aliases != nil || co.profile != "" || co.conditions != nil || co.primary || co.prioritized {
+		return fmt.Errorf("%s WithUniqueType, WithNamePattern, WithAlias, WithProfile, WithCondition, WithPrimary or WithPriority: %w", op, ErrNotSupported)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mp3cko/registry/access"
+)
+
+func TestCollection_Slice(t *testing.T) {
+	r := newTestReg(t)
+
+	for i := range 3 {
+		if err := Append(ExportedNamedTester{ID: i}, WithRegistry(r)); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	_ = Append(ExportedNamedTester{ID: 10}, WithRegistry(r), WithName("other"))
+
+	got, err := GetSlice[ExportedNamedTester](WithRegistry(r))
+	if err != nil || !slices.Equal(got, []ExportedNamedTester{{0}, {1}, {2}}) {
+		t.Fatalf("GetSlice() = %v, %v", got, err)
+	}
+
+	if got, _ := GetSlice[ExportedNamedTester](WithRegistry(r), WithName("other")); len(got) != 1 || got[0].ID != 10 {
+		t.Fatalf("GetSlice named = %v", got)
+	}
+
+	// collections are separate from instances
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() err = %v, want ErrNotFound", err)
+	}
+
+	if got, err := GetSlice[int](WithRegistry(r)); err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("GetSlice empty = %#v, %v, want an empty slice", got, err)
+	}
+
+	// the returned slice is a copy
+	got[0].ID = 100
+	if again, _ := GetSlice[ExportedNamedTester](WithRegistry(r)); again[0].ID != 0 {
+		t.Fatalf("GetSlice() returned the stored slice")
+	}
+}
+
+func TestCollection_Map(t *testing.T) {
+	r := newTestReg(t)
+
+	if err := Put("a", ExportedNamedTester{ID: 1}, WithRegistry(r)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	_ = Put("b", ExportedNamedTester{ID: 2}, WithRegistry(r))
+	_ = Put("a", ExportedNamedTester{ID: 3}, WithRegistry(r))
+
+	got, err := GetMap[string, ExportedNamedTester](WithRegistry(r))
+	if err != nil || len(got) != 2 || got["a"].ID != 3 || got["b"].ID != 2 {
+		t.Fatalf("GetMap() = %v, %v", got, err)
+	}
+
+	if err := Put("a", ExportedNamedTester{ID: 4}, WithRegistry(r), WithUniqueName()); !errors.Is(err, ErrNotUniqueName) {
+		t.Fatalf("Put duplicate err = %v, want ErrNotUniqueName", err)
+	}
+
+	unique := newTestReg(t, WithUniqueName())
+	_ = Put(1, "one", WithRegistry(unique))
+	if err := Put(1, "uno", WithRegistry(unique)); !errors.Is(err, ErrNotUniqueName) {
+		t.Fatalf("Put duplicate err = %v, want ErrNotUniqueName", err)
+	}
+
+	if got, err := GetMap[int, int](WithRegistry(r)); err != nil || got == nil || len(got) != 0 {
+		t.Fatalf("GetMap empty = %#v, %v, want an empty map", got, err)
+	}
+}
+
+func TestCollection_Remove(t *testing.T) {
+	r := newTestReg(t)
+
+	for _, id := range []int{1, 2, 1, 3} {
+		_ = Append(ExportedNamedTester{ID: id}, WithRegistry(r))
+	}
+
+	if err := Remove(ExportedNamedTester{ID: 1}, WithRegistry(r)); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if got, _ := GetSlice[ExportedNamedTester](WithRegistry(r)); !slices.Equal(got, []ExportedNamedTester{{2}, {3}}) {
+		t.Fatalf("GetSlice after Remove = %v, want [2 3]", got)
+	}
+
+	if err := Remove(ExportedNamedTester{ID: 1}, WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Remove missing err = %v, want ErrNotFound", err)
+	}
+
+	if err := Remove([]int{1}, WithRegistry(r)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Remove not comparable err = %v, want ErrNotSupported", err)
+	}
+
+	_ = Put("a", ExportedNamedTester{ID: 1}, WithRegistry(r))
+	_ = Put("b", ExportedNamedTester{ID: 2}, WithRegistry(r))
+
+	if err := Delete[string, ExportedNamedTester]("a", WithRegistry(r)); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if got, _ := GetMap[string, ExportedNamedTester](WithRegistry(r)); len(got) != 1 || got["b"].ID != 2 {
+		t.Fatalf("GetMap after Delete = %v, want only b", got)
+	}
+
+	if err := Delete[string, ExportedNamedTester]("a", WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete missing err = %v, want ErrNotFound", err)
+	}
+
+	// emptied collections are no longer listed
+	_ = Remove(ExportedNamedTester{ID: 2}, WithRegistry(r))
+	_ = Remove(ExportedNamedTester{ID: 3}, WithRegistry(r))
+	_ = Delete[string, ExportedNamedTester]("b", WithRegistry(r))
+
+	if entries, err := Entries(WithRegistry(r)); err != nil || len(entries) != 0 {
+		t.Fatalf("Entries() = %v, %v, want none", entries, err)
+	}
+}
+
+func TestCollection_Registry(t *testing.T) {
+	r := newTestReg(t, WithValidator(func(v ExportedNamedTester) error {
+		if v.ID < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}))
+
+	_ = Append(ExportedNamedTester{ID: 1}, WithRegistry(r), WithNamespace("http"))
+	_ = Put("x", ExportedNamedTester{ID: 2}, WithRegistry(r), WithNamespace("http"))
+
+	if err := Append(ExportedNamedTester{ID: -1}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Append invalid err = %v, want ErrInvalidValue", err)
+	}
+
+	if err := Append(struct{}{}, WithRegistry(r), WithNamedness(access.NamedType)); !errors.Is(err, ErrNamednessTooLow) {
+		t.Fatalf("Append anonymous err = %v, want ErrNamednessTooLow", err)
+	}
+
+	// keys only select elements, they are not checked
+	if err := Put(stringerTester{ID: 1}, ExportedNamedTester{ID: 1}, WithRegistry(r), WithName("keyed"), WithAccessibility(access.AccessibleEverywhere)); err != nil {
+		t.Fatalf("Put unexported key error = %v", err)
+	}
+
+	_ = Delete[stringerTester, ExportedNamedTester](stringerTester{ID: 1}, WithRegistry(r), WithName("keyed"))
+
+	entries, err := Entries(WithRegistry(r))
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Entries() = %v, %v", entries, err)
+	}
+
+	if entries[0].Type != reflect.TypeFor[[]ExportedNamedTester]() || entries[0].Name != "http" || entries[0].Len != 1 {
+		t.Fatalf("Entries()[0] = %+v", entries[0])
+	}
+
+	clone := newTestReg(t, WithCloneRegistry(r))
+	if got, _ := GetSlice[ExportedNamedTester](WithRegistry(clone), WithNamespace("http")); len(got) != 1 {
+		t.Fatalf("GetSlice from clone = %v", got)
+	}
+
+	if err := UnsetNamespace("http", WithRegistry(r)); err != nil {
+		t.Fatalf("UnsetNamespace() error = %v", err)
+	}
+
+	if got, _ := GetMap[string, ExportedNamedTester](WithRegistry(r), WithNamespace("http")); len(got) != 0 {
+		t.Fatalf("GetMap after UnsetNamespace = %v", got)
+	}
+
+	if got, _ := GetSlice[ExportedNamedTester](WithRegistry(clone), WithNamespace("http")); len(got) != 1 {
+		t.Fatalf("GetSlice from clone after UnsetNamespace = %v", got)
+	}
+}
+
+func TestCollection_Options(t *testing.T) {
+	r := newTestReg(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"Append WithUniqueName", Append(1, WithRegistry(r), WithUniqueName()), ErrNotSupported},
+		{"Append WithAlias", Append(1, WithRegistry(r), WithAlias("x")), ErrNotSupported},
+		{"Put WithProfile", Put(1, 1, WithRegistry(r), WithProfile("dev")), ErrNotSupported},
+		{"GetSlice WithUniqueType", func() error { _, err := GetSlice[int](WithRegistry(r), WithUniqueType()); return err }(), ErrNotSupported},
+		{"Remove WithUniqueName", Remove(1, WithRegistry(r), WithUniqueName()), ErrNotSupported},
+		{"Delete WithAlias", Delete[int, int](1, WithRegistry(r), WithAlias("x")), ErrNotSupported},
+		{"GetMap WithNamePattern", func() error { _, err := GetMap[int, int](WithRegistry(r), WithNamePattern(Glob("*"))); return err }(), ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Fatalf("%s err = %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package reg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+)
+
+// Condition guards a registration, see [WithCondition].
+//
+// It is called without holding the registry lock, so it can query r (ex. Get[T](WithRegistry(r))).
+// It is called again if the entries change before the registration is made, so it should not have side effects.
+type Condition func(r Registry) bool
+
+// OnMissing holds if no instance of T is registered, opts (ex. WithName) narrow down the instance. Use it for defaults applications can override:
+//
+//	Set[Logger](defaultLogger{}, WithCondition(OnMissing[Logger]()))
+func OnMissing[T any](opts ...Option) Condition {
+	return func(r Registry) bool {
+		_, err := Get[T](append([]Option{WithRegistry(r)}, opts...)...)
+
+		return errors.Is(err, ErrNotFound)
+	}
+}
+
+// OnPresent holds if an instance of T is registered, opts (ex. WithName) narrow down the instance
+func OnPresent[T any](opts ...Option) Condition {
+	return func(r Registry) bool {
+		_, err := Get[T](append([]Option{WithRegistry(r)}, opts...)...)
+
+		return err == nil
+	}
+}
+
+// OnEnv holds if the environment variable key is set (even if empty)
+func OnEnv(key string) Condition {
+	return func(Registry) bool {
+		_, ok := os.LookupEnv(key)
+
+		return ok
+	}
+}
+
+// pendingSet is a registration waiting for its conditions to be evaluated
+type pendingSet struct {
+	rt         reflect.Type
+	key        entryKey // instance the registration makes, resolved when registering
+	as         []reflect.Type
+	build      func(r Registry) (any, error) // returns the instance, called only if all conditions hold
+	conditions []Condition
+	co         callOptions // options of the original call, with the caller package resolved
+	owner      Module      // module that made the registration, if any
+}
+
+// Resolve evaluates the conditional registrations of the default registry, see [Registry.Resolve]
+func Resolve() error {
+	return defReg.Load().Resolve()
+}
+
+// Resolve evaluates the pending conditional registrations (see [WithCondition]) in order of registration, registering the ones whose conditions hold.
+// Registrations made by earlier ones are visible to the conditions of later ones.
+//
+// After Resolve conditional registrations are evaluated immediately. Failed registrations don't stop the others, all their errors are returned joined.
+func (t *registry) Resolve() error {
+	t.lock(OpResolve)
+	pending := t.pending
+	t.pending = nil
+	t.resolved = true
+	if len(pending) > 0 {
+		t.version++
+	}
+	t.unlock()
+
+	var errs []error
+	for _, p := range pending {
+		if err := t.runPending(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	t.lock(OpResolve)
+	t.log(OpResolve, nil, "", err)
+	t.unlock()
+
+	return err
+}
+
+// register registers p, right away if it has no conditions or the registry is resolved, otherwise it is queued until Resolve.
+//
+// Caller must hold the lock and the call options, they are released before the conditions are evaluated.
+func (t *registry) register(p *pendingSet) error {
+	if err := checkMutationOpts(OpSet, t.callOptions); err != nil {
+		t.observe(OpSet, p.rt, err)
+		t.cleanup()
+
+		return err
+	}
+
+	p.co = *t.callOptions
+	p.co.conditions = nil
+	p.key = entryKey{p.rt, t.entryName()}
+	p.owner = t.installing
+
+	// the stack is different once the registration runs
+	if p.co.callerPkg == "" {
+		p.co.callerPkg = t.caller()
+		p.co.callerSkip = 0
+	}
+
+	if len(p.conditions) > 0 && !t.resolved {
+		t.pending = append(t.pending, p)
+		// pending registrations are part of the entries, Install must not lose them
+		t.version++
+		t.cleanup()
+
+		return nil
+	}
+
+	t.cleanup()
+
+	return t.runPending(p)
+}
+
+// runPending registers p if all its conditions hold, caller must not hold the lock.
+//
+// The conditions are evaluated without holding the lock, so they are evaluated again if the entries changed
+// in the meantime (ex. a concurrent Set of the instance a fallback is guarded by OnMissing against).
+func (t *registry) runPending(p *pendingSet) error {
+	var (
+		val   any
+		err   error
+		built bool
+	)
+
+	for {
+		t.lock(OpSet)
+		version := t.version
+		t.unlock()
+
+		for _, c := range p.conditions {
+			if !c(t) {
+				return nil
+			}
+		}
+
+		t.lock(OpSet)
+		if t.version != version {
+			t.unlock()
+			continue
+		}
+
+		if !built {
+			g := t.startBuild(p.key)
+			t.unlock()
+
+			val, err = t.buildPending(p, g)
+			built = true
+
+			t.lock(OpSet)
+			t.endBuild(g)
+
+			// the instance is built once, but the factory or a concurrent call may have changed what the conditions see
+			if err == nil && len(p.conditions) > 0 && t.version != version {
+				t.unlock()
+				continue
+			}
+		}
+
+		break
+	}
+
+	defer t.cleanup()
+
+	co := p.co
+	t.callOptions = &co
+
+	if err != nil {
+		err = fmt.Errorf("Set '%s' factory failed: %w", p.rt, err)
+	} else {
+		installing := t.installing
+		t.installing = p.owner
+
+		err = setEntry(t, p.rt, val, p.as, false)
+		t.installing = installing
+	}
+
+	t.observe(OpSet, p.rt, err)
+
+	return err
+}
+
+// buildPending builds the instance of p, if the factory panics the build started by goroutine g is ended before the panic is propagated.
+// Caller must not hold the lock
+func (t *registry) buildPending(p *pendingSet, g uint64) (val any, err error) {
+	completed := false
+
+	defer func() {
+		if completed {
+			return
+		}
+
+		t.lock(OpSet)
+		t.endBuild(g)
+		t.unlock()
+	}()
+
+	val, err = p.build(t)
+	completed = true
+
+	return val, err
+}
+
+// Provide registers the instance returned by fn like [Set]. Unlike Set the instance is only built if it is registered,
+// so combined with [WithCondition] it avoids building defaults which are overridden:
+//
+//	Provide(func(r Registry) (Cache, error) { return newMemCache(), nil }, WithCondition(OnMissing[Cache]()))
+//
+// fn runs without holding the registry lock, so it can retrieve its dependencies from r. They are recorded and checked for cycles like the ones
+// of [GetOrCreate], with the same limitation: entries resolved by goroutines fn starts are not attributed to it. Supports the same options as Set.
+func Provide[T any](fn func(r Registry) (T, error), opts ...Option) error {
+	if fn == nil {
+		return fmt.Errorf("Provide nil factory: %w", ErrBadOption)
+	}
+
+	r, err := acquire(OpSet, reflect.TypeFor[T](), opts)
+	if err != nil {
+		return err
+	}
+
+	return r.register(&pendingSet{
+		rt:         reflect.TypeFor[T](),
+		build:      func(r Registry) (any, error) { return fn(r) },
+		conditions: slices.Clone(r.callOptions.conditions),
+	})
+}
+
+// setConditional registers val under rt and as once the conditions passed using WithCondition hold. Caller must hold the lock and the call options, they are released
+func setConditional(r *registry, rt reflect.Type, val any, as []reflect.Type) error {
+	return r.register(&pendingSet{
+		rt:         rt,
+		as:         as,
+		build:      func(Registry) (any, error) { return val, nil },
+		conditions: slices.Clone(r.callOptions.conditions),
+	})
+}
@@ -0,0 +1,229 @@
+package reg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCondition_DefaultsOverridden(t *testing.T) {
+	r := newTestReg(t)
+
+	// library default registered before the application override
+	if err := Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithCondition(OnMissing[ExportedNamedTester]())); err != nil {
+		t.Fatalf("Set default error = %v", err)
+	}
+
+	var built bool
+	err := Provide(func(Registry) (*ExportedNamedTester, error) {
+		built = true
+		return &ExportedNamedTester{}, nil
+	}, WithRegistry(r), WithCondition(OnMissing[ExportedNamedTester]()))
+	if err != nil {
+		t.Fatalf("Provide() error = %v", err)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before Resolve err = %v, want ErrNotFound", err)
+	}
+
+	if err := Set(ExportedNamedTester{ID: 2}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set override error = %v", err)
+	}
+
+	if err := r.Resolve(); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 2 {
+		t.Fatalf("Get() = %v, want the override", got)
+	}
+
+	if built {
+		t.Fatalf("factory of a skipped registration was called")
+	}
+}
+
+func TestCondition_Resolve(t *testing.T) {
+	r := newTestReg(t)
+
+	// the second registration sees the first one
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithCondition(OnMissing[ExportedNamedTester]()))
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("replica"), WithCondition(OnPresent[ExportedNamedTester]()))
+
+	// the factory retrieves its dependency
+	_ = Provide(func(r Registry) (*ExportedNamedTester, error) {
+		dep, err := Get[ExportedNamedTester](WithRegistry(r), WithName("replica"))
+		return &dep, err
+	}, WithRegistry(r), WithCondition(OnPresent[ExportedNamedTester](WithName("replica"))))
+
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithName("env"), WithCondition(OnEnv("REG_TEST_UNSET_ENV")))
+
+	if err := r.Resolve(); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r), WithName("replica")); err != nil || got.ID != 2 {
+		t.Fatalf("Get replica = %v, %v", got, err)
+	}
+
+	if got, err := Get[*ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 2 {
+		t.Fatalf("Get provided = %v, %v", got, err)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r), WithName("env")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get env err = %v, want ErrNotFound", err)
+	}
+
+	// evaluated immediately once resolved
+	t.Setenv("REG_TEST_SET_ENV", "")
+	if err := Set(ExportedNamedTester{ID: 4}, WithRegistry(r), WithName("env"), WithCondition(OnEnv("REG_TEST_SET_ENV"))); err != nil {
+		t.Fatalf("Set after Resolve error = %v", err)
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r), WithName("env")); err != nil || got.ID != 4 {
+		t.Fatalf("Get env = %v, %v", got, err)
+	}
+}
+
+func TestCondition_Errors(t *testing.T) {
+	r := newTestReg(t, WithUniqueType())
+
+	boom := errors.New("boom")
+
+	_ = Set(ExportedNamedTester{}, WithRegistry(r), WithCondition(OnEnv("PATH")))
+	_ = Set(ExportedNamedTester{}, WithRegistry(r), WithName("second"), WithCondition(OnEnv("PATH")))
+	_ = Provide(func(Registry) (int, error) { return 0, boom }, WithRegistry(r), WithCondition(OnEnv("PATH")))
+
+	err := r.Resolve()
+	if !errors.Is(err, ErrNotUniqueType) || !errors.Is(err, boom) {
+		t.Fatalf("Resolve() err = %v, want ErrNotUniqueType and the factory error", err)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil {
+		t.Fatalf("Get() error = %v, failed registrations must not stop the others", err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"NewRegistry", func() error { _, err := NewRegistry(WithCondition(OnEnv("X"))); return err }(), ErrNotSupported},
+		{"nil condition", Set(1, WithRegistry(r), WithCondition(nil)), ErrBadOption},
+		{"nil factory", Provide[int](nil, WithRegistry(r)), ErrBadOption},
+		{"Get", func() error { _, err := Get[int](WithRegistry(r), WithCondition(OnEnv("X"))); return err }(), ErrNotSupported},
+		{"Swap", func() error { _, _, err := Swap(1, WithRegistry(r), WithCondition(OnEnv("X"))); return err }(), ErrNotSupported},
+		{"Set WithNamePattern", Set(1, WithRegistry(r), WithCondition(OnEnv("X")), WithNamePattern(Glob("*"))), ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Fatalf("%s err = %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+}
+
+func TestCondition_ProvidePanic(t *testing.T) {
+	r := newTestReg(t)
+
+	func() {
+		defer func() {
+			if rec := recover(); rec == nil {
+				t.Fatalf("Provide did not propagate the factory panic")
+			}
+		}()
+
+		_ = Provide(func(Registry) (ExportedNamedTester, error) {
+			panic("boom")
+		}, WithRegistry(r))
+	}()
+
+	if len(r.building) != 0 {
+		t.Fatalf("builds left after the factory panicked: %v", r.building)
+	}
+
+	// the panicked build must not be seen as an instance depending on itself
+	if err := Set(ExportedNamedTester{ID: 1}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 1 {
+		t.Fatalf("Get() = %+v, %v; want ID 1, nil", got, err)
+	}
+}
+
+func TestCondition_Install(t *testing.T) {
+	r := newTestReg(t)
+
+	m := &conditionalModule{}
+	if err := r.Install(m); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if err := r.Resolve(); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	entries, err := Entries(WithRegistry(r))
+	if err != nil || len(entries) != 1 || entries[0].Module != m {
+		t.Fatalf("Entries() = %v, %v, want the default owned by the module", entries, err)
+	}
+}
+
+// conditionalModule registers a default
+type conditionalModule struct{}
+
+func (t *conditionalModule) Register(r Registry) error {
+	return Set(ExportedNamedTester{}, WithRegistry(r), WithCondition(OnMissing[ExportedNamedTester]()))
+}
+
+func TestCondition_ConcurrentSet(t *testing.T) {
+	r := newTestReg(t)
+	if err := r.Resolve(); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// setConcurrently registers the override from another goroutine, like an application racing the library default
+	setConcurrently := func(id int) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = Set(ExportedNamedTester{ID: id}, WithRegistry(r))
+		}()
+		<-done
+	}
+
+	// the override lands after the condition was evaluated
+	missing := OnMissing[ExportedNamedTester]()
+	cond := func(r Registry) bool {
+		ok := missing(r)
+		if ok {
+			setConcurrently(2)
+		}
+
+		return ok
+	}
+
+	if err := Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithCondition(cond)); err != nil {
+		t.Fatalf("Set default error = %v", err)
+	}
+
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 2 {
+		t.Fatalf("Get() = %v, want the concurrent override", got)
+	}
+
+	// the override lands while the factory runs
+	_ = Unset(ExportedNamedTester{}, WithRegistry(r))
+
+	err := Provide(func(Registry) (ExportedNamedTester, error) {
+		setConcurrently(4)
+		return ExportedNamedTester{ID: 3}, nil
+	}, WithRegistry(r), WithCondition(OnMissing[ExportedNamedTester]()))
+	if err != nil {
+		t.Fatalf("Provide() error = %v", err)
+	}
+
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 4 {
+		t.Fatalf("Get() = %v, want the concurrent override", got)
+	}
+}
@@ -0,0 +1,267 @@
+package reg
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source populates a config struct, see [BindConfig]. Sources only set the fields they have values for, so later sources override earlier ones.
+type Source interface {
+	Load(dst any) error
+}
+
+// SourceFunc is a function implementing [Source]
+type SourceFunc func(dst any) error
+
+func (t SourceFunc) Load(dst any) error {
+	return t(dst)
+}
+
+// Env populates fields tagged `env:"NAME"` from the environment variables prefix+NAME
+func Env(prefix string) Source {
+	return SourceFunc(func(dst any) error {
+		return bindTagged(dst, "env", func(key string) (string, bool) {
+			return os.LookupEnv(prefix + key)
+		})
+	})
+}
+
+// Map populates fields tagged `env:"NAME"` from values[NAME], useful as an in-memory replacement of [Env] in tests
+func Map(values map[string]string) Source {
+	return SourceFunc(func(dst any) error {
+		return bindTagged(dst, "env", func(key string) (string, bool) {
+			val, ok := values[key]
+			return val, ok
+		})
+	})
+}
+
+// JSONFile populates the config from the JSON file at path (using the json tags), the file is read again on every load
+func JSONFile(path string) Source {
+	return SourceFunc(func(dst any) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(data, dst)
+	})
+}
+
+// JSONReader populates the config from JSON read from r (using the json tags). r is read once, reloads reuse its contents
+func JSONReader(r io.Reader) Source {
+	var (
+		once sync.Once
+		data []byte
+		err  error
+	)
+
+	return SourceFunc(func(dst any) error {
+		once.Do(func() { data, err = io.ReadAll(r) })
+		if err != nil {
+			return err
+		}
+
+		return json.Unmarshal(data, dst)
+	})
+}
+
+// Binding is a config bound to its sources using [BindConfig]
+type Binding[T any] struct {
+	mu      sync.Mutex
+	sources []Source
+	opts    []Option
+}
+
+// BindConfig populates a new config struct T (or *T) from the sources in order and registers it like [Set], using the options.
+//
+// Fields are first set to their `default:"..."` tag values, then each source sets the fields it has values for.
+// Supported field types are strings, bools, numbers, time.Duration, []string (comma separated) and encoding.TextUnmarshaler implementations,
+// untagged struct fields are populated recursively. The config is validated like every other instance (see [Validator] and [WithValidator]).
+//
+//	type Config struct {
+//		Port    int           `env:"PORT" json:"port" default:"8080"`
+//		Timeout time.Duration `env:"TIMEOUT" json:"timeout"`
+//	}
+//
+//	b, err := BindConfig[Config]([]Source{JSONFile("config.json"), Env("APP_")}, WithName("api"))
+//	...
+//	err = b.Reload() // re-binds and replaces the registered config
+func BindConfig[T any](sources []Source, opts ...Option) (*Binding[T], error) {
+	b := &Binding[T]{sources: sources, opts: opts}
+
+	val, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Set(val, opts...); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Reload populates a new config from the sources and replaces the registered one like [Swap], keeping its aliases.
+// On error the registered config is left unchanged
+func (t *Binding[T]) Reload() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	val, err := t.load()
+	if err != nil {
+		return err
+	}
+
+	_, _, err = Swap(val, t.opts...)
+
+	return err
+}
+
+// Get returns the registered config
+func (t *Binding[T]) Get() (T, error) {
+	return Get[T](t.opts...)
+}
+
+// load populates a new config from the defaults and the sources
+func (t *Binding[T]) load() (T, error) {
+	rt := reflect.TypeFor[T]()
+
+	var val reflect.Value // pointer to the config struct
+	switch {
+	case rt.Kind() == reflect.Struct:
+		val = reflect.New(rt)
+	case rt.Kind() == reflect.Pointer && rt.Elem().Kind() == reflect.Struct:
+		val = reflect.New(rt.Elem())
+	default:
+		return zeroValue[T](), fmt.Errorf("BindConfig '%s' failed, not a struct: %w", rt, ErrNotSupported)
+	}
+
+	if err := bindTagged(val.Interface(), "default", func(def string) (string, bool) { return def, true }); err != nil {
+		return zeroValue[T](), fmt.Errorf("BindConfig '%s' defaults: %w", rt, err)
+	}
+
+	for i, src := range t.sources {
+		if err := src.Load(val.Interface()); err != nil {
+			return zeroValue[T](), fmt.Errorf("BindConfig '%s' source %d failed: %w", rt, i, err)
+		}
+	}
+
+	if rt.Kind() == reflect.Pointer {
+		return val.Interface().(T), nil
+	}
+
+	return val.Elem().Interface().(T), nil
+}
+
+// bindTagged sets every field of the struct pointed to by dst tagged with tag, using the value lookup returns for the tag value
+func bindTagged(dst any, tag string, lookup func(key string) (string, bool)) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("binding '%T', not a pointer to a struct: %w", dst, ErrNotSupported)
+	}
+
+	return bindFields(v.Elem(), tag, lookup)
+}
+
+func bindFields(v reflect.Value, tag string, lookup func(key string) (string, bool)) error {
+	textUnmarshaler := reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key, tagged := field.Tag.Lookup(tag)
+		if !tagged {
+			if field.Type.Kind() == reflect.Struct && !reflect.PointerTo(field.Type).Implements(textUnmarshaler) {
+				if err := bindFields(v.Field(i), tag, lookup); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if key == "-" {
+			continue
+		}
+
+		s, ok := lookup(key)
+		if !ok {
+			continue
+		}
+
+		if err := setField(v.Field(i), s); err != nil {
+			return fmt.Errorf("field %s (%s %q): %w", field.Name, tag, key, err)
+		}
+	}
+
+	return nil
+}
+
+// setField parses s into the field
+func setField(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+		}
+
+		return nil
+	}
+
+	var err error
+
+	switch {
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		var d time.Duration
+		d, err = time.ParseDuration(s)
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(s)
+	case v.Kind() == reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		v.SetBool(b)
+	case v.CanInt():
+		var n int64
+		n, err = strconv.ParseInt(s, 0, v.Type().Bits())
+		v.SetInt(n)
+	case v.CanUint():
+		var n uint64
+		n, err = strconv.ParseUint(s, 0, v.Type().Bits())
+		v.SetUint(n)
+	case v.CanFloat():
+		var f float64
+		f, err = strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(f)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		slice := reflect.Zero(v.Type())
+		if s != "" {
+			// set the elements one by one, converting []string panics for slices of named string types
+			parts := strings.Split(s, ",")
+			slice = reflect.MakeSlice(v.Type(), len(parts), len(parts))
+			for i, part := range parts {
+				slice.Index(i).SetString(strings.TrimSpace(part))
+			}
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type '%s': %w", v.Type(), ErrNotSupported)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package reg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testDBConfig struct {
+	Host string `env:"DB_HOST" json:"host" default:"localhost"`
+}
+
+type testConfig struct {
+	Port    int           `env:"PORT" json:"port" default:"8080"`
+	Debug   bool          `env:"DEBUG" json:"debug"`
+	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+	Tags    []string      `env:"TAGS" json:"tags"`
+	Ignored string        `env:"-"`
+	DB      testDBConfig
+}
+
+func TestBindConfig(t *testing.T) {
+	r := newTestReg(t)
+
+	b, err := BindConfig[testConfig]([]Source{Map(map[string]string{
+		"PORT":    "9090",
+		"TAGS":    "a, b",
+		"DB_HOST": "db",
+		"-":       "nope",
+	})}, WithRegistry(r))
+	if err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	got, err := Get[testConfig](WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if got.Port != 9090 || got.Timeout != 5*time.Second || got.DB.Host != "db" || got.Ignored != "" || strings.Join(got.Tags, "|") != "a|b" {
+		t.Fatalf("bound config = %+v", got)
+	}
+
+	if bound, err := b.Get(); err != nil || bound.Port != 9090 {
+		t.Fatalf("Binding.Get() = %+v, %v", bound, err)
+	}
+}
+
+// testLevel is a named string type, binding slices of it must not panic
+type testLevel string
+
+func TestBindConfig_NamedStringSlice(t *testing.T) {
+	type levels struct {
+		Levels []testLevel `env:"LEVELS"`
+	}
+
+	r := newTestReg(t)
+
+	if _, err := BindConfig[levels]([]Source{Map(map[string]string{"LEVELS": "info, warn"})}, WithRegistry(r)); err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	got, err := Get[levels](WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if len(got.Levels) != 2 || got.Levels[0] != "info" || got.Levels[1] != "warn" {
+		t.Fatalf("bound levels = %q, want [info warn]", got.Levels)
+	}
+}
+
+func TestBindConfig_SourcesOverride(t *testing.T) {
+	r := newTestReg(t)
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"port": 1, "debug": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("APP_PORT", "2")
+
+	_, err := BindConfig[*testConfig]([]Source{JSONFile(path), JSONReader(strings.NewReader(`{"tags": ["x"]}`)), Env("APP_")}, WithRegistry(r), WithName("app"))
+	if err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	got, err := Get[*testConfig](WithRegistry(r), WithName("app"))
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if got.Port != 2 || !got.Debug || len(got.Tags) != 1 || got.DB.Host != "localhost" {
+		t.Fatalf("bound config = %+v", got)
+	}
+}
+
+func TestBindConfig_Reload(t *testing.T) {
+	values := map[string]string{"PORT": "1"}
+	r := newTestReg(t, WithValidator(func(c testConfig) error {
+		if c.Port <= 0 {
+			return errors.New("port must be positive")
+		}
+		return nil
+	}))
+
+	b, err := BindConfig[testConfig]([]Source{Map(values)}, WithRegistry(r), WithAlias("current"))
+	if err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	values["PORT"] = "2"
+	if err := b.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if got, _ := Get[testConfig](WithRegistry(r)); got.Port != 2 {
+		t.Fatalf("Port after reload = %d, want 2", got.Port)
+	}
+
+	if got, _ := Get[testConfig](WithRegistry(r), WithName("current")); got.Port != 2 {
+		t.Fatalf("Port of alias after reload = %d, want 2", got.Port)
+	}
+
+	// invalid configs are rejected and the registered one is kept
+	values["PORT"] = "-1"
+	if err := b.Reload(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Reload() err = %v, want ErrInvalidValue", err)
+	}
+
+	values["PORT"] = "nan"
+	if err := b.Reload(); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Reload() err = %v, want ErrInvalidValue", err)
+	}
+
+	if got, _ := Get[testConfig](WithRegistry(r)); got.Port != 2 {
+		t.Fatalf("Port after failed reload = %d, want 2", got.Port)
+	}
+}
+
+func TestBindConfig_Invalid(t *testing.T) {
+	r := newTestReg(t)
+
+	if _, err := BindConfig[int](nil, WithRegistry(r)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("BindConfig[int] err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := BindConfig[testConfig]([]Source{Map(map[string]string{"DEBUG": "maybe"})}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("BindConfig() err = %v, want ErrInvalidValue", err)
+	}
+
+	if _, err := BindConfig[testConfig]([]Source{JSONFile(filepath.Join(t.TempDir(), "missing.json"))}, WithRegistry(r)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("BindConfig() err = %v, want os.ErrNotExist", err)
+	}
+
+	if _, err := Get[testConfig](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() err = %v, want ErrNotFound after failed binds", err)
+	}
+}
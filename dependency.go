@@ -0,0 +1,261 @@
+package reg
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EntryRef identifies an instance by type and name
+type EntryRef struct {
+	Type reflect.Type
+	Name string
+}
+
+func (t EntryRef) String() string {
+	if t.Name == "" {
+		return t.Type.String()
+	}
+
+	return fmt.Sprintf("%s(%s)", t.Type, t.Name)
+}
+
+// Dependency is an edge of the [DependencyGraph]: From resolved To while it was being built by a factory (see [GetOrCreate] and [Provide])
+type Dependency struct {
+	From, To EntryRef
+}
+
+// DependencyGraph describes the registered entries and the dependencies between them, recorded as factories resolve other entries
+type DependencyGraph struct {
+	Entries      []EntryInfo  // as returned by Entries
+	Dependencies []Dependency // sorted by From and To
+
+	caller string // package the graph was retrieved from, accessibility is reported relative to it
+}
+
+// Missing returns the dependencies which are not registered (ex. a factory failed to resolve them), sorted
+func (t DependencyGraph) Missing() []EntryRef {
+	registered := map[EntryRef]bool{}
+	for _, e := range t.Entries {
+		registered[EntryRef{e.Type, e.Name}] = true
+	}
+
+	var missing []EntryRef
+	for _, d := range t.Dependencies {
+		if !registered[d.To] && !slices.Contains(missing, d.To) {
+			missing = append(missing, d.To)
+		}
+	}
+
+	slices.SortFunc(missing, compareRefs)
+
+	return missing
+}
+
+// Graph returns the dependency graph of the default registry, see [Registry.Graph]
+func Graph() DependencyGraph {
+	return defReg.Load().Graph()
+}
+
+// Graph returns the entries of the registry and the dependencies recorded between them.
+//
+// A dependency is recorded whenever a factory resolves another entry of the registry using [Get] or [GetOrCreate] while it builds its own instance,
+// names are relative to the registry namespace.
+func (t *registry) Graph() DependencyGraph {
+	t.lock(OpGetAll)
+	defer t.cleanup()
+
+	t.ensureCallOpts()
+	ns := t.namespace()
+
+	g := DependencyGraph{Entries: entries(t), caller: t.caller()}
+
+	for from, deps := range t.deps {
+		for to := range deps {
+			g.Dependencies = append(g.Dependencies, Dependency{From: t.ref(ns, from), To: t.ref(ns, to)})
+		}
+	}
+
+	slices.SortFunc(g.Dependencies, func(a, b Dependency) int {
+		return cmp.Or(compareRefs(a.From, b.From), compareRefs(a.To, b.To))
+	})
+
+	return g
+}
+
+// ref returns the reference to key with the name relative to ns
+func (t *registry) ref(ns string, key entryKey) EntryRef {
+	name, _ := relName(ns, key.name)
+
+	return EntryRef{key.rt, name}
+}
+
+// startBuild records that the current goroutine builds the instance stored under key, returning the goroutine id for endBuild.
+// Nothing is recorded if the goroutine id can't be read, dependencies are then neither tracked nor checked for cycles. Caller must hold the lock
+func (t *registry) startBuild(key entryKey) uint64 {
+	g, ok := goid()
+	if !ok {
+		return 0
+	}
+
+	if t.building == nil {
+		t.building = map[uint64][]entryKey{}
+	}
+
+	t.building[g] = append(t.building[g], key)
+
+	return g
+}
+
+// endBuild records that goroutine g finished building its last instance, caller must hold the lock
+func (t *registry) endBuild(g uint64) {
+	stack := t.building[g]
+	if len(stack) <= 1 {
+		delete(t.building, g)
+		return
+	}
+
+	t.building[g] = stack[:len(stack)-1]
+}
+
+// depend records key as a dependency of the instance the current goroutine is building, if any.
+//
+// Returns ErrCycle if key depends on that instance, directly or through other instances being built. Caller must hold the lock
+func (t *registry) depend(key entryKey) error {
+	if len(t.building) == 0 {
+		return nil
+	}
+
+	g, ok := goid()
+	if !ok {
+		return nil
+	}
+
+	stack := t.building[g]
+	if len(stack) == 0 {
+		return nil
+	}
+
+	from := stack[len(stack)-1]
+
+	if t.deps == nil {
+		t.deps = map[entryKey]map[entryKey]bool{}
+	}
+
+	if t.deps[from] == nil {
+		t.deps[from] = map[entryKey]bool{}
+	}
+
+	t.deps[from][key] = true
+
+	path := t.buildPath(key, from)
+	if path == nil {
+		return nil
+	}
+
+	ns := t.namespace()
+
+	refs := make([]string, 0, len(path)+1)
+	for _, k := range append(path, key) {
+		refs = append(refs, t.ref(ns, k).String())
+	}
+
+	return fmt.Errorf("resolving '%s' failed: %w: %s", t.ref(ns, key), ErrCycle, strings.Join(refs, " -> "))
+}
+
+// buildPath returns the dependency path from key to target through instances being built, nil if there is none. Caller must hold the lock
+func (t *registry) buildPath(key, target entryKey) []entryKey {
+	building := map[entryKey]bool{}
+	for _, stack := range t.building {
+		for _, k := range stack {
+			building[k] = true
+		}
+	}
+
+	visited := map[entryKey]bool{}
+
+	var visit func(k entryKey) []entryKey
+	visit = func(k entryKey) []entryKey {
+		if k == target {
+			return []entryKey{k}
+		}
+
+		if visited[k] || !building[k] {
+			return nil
+		}
+
+		visited[k] = true
+
+		// sorted for deterministic paths
+		deps := make([]entryKey, 0, len(t.deps[k]))
+		for dep := range t.deps[k] {
+			deps = append(deps, dep)
+		}
+
+		slices.SortFunc(deps, compareKeys)
+
+		for _, dep := range deps {
+			if path := visit(dep); path != nil {
+				return append([]entryKey{k}, path...)
+			}
+		}
+
+		return nil
+	}
+
+	if !building[key] {
+		return nil
+	}
+
+	return visit(key)
+}
+
+// cloneDeps returns a deep copy of the recorded dependencies
+func (t *registry) cloneDeps() map[entryKey]map[entryKey]bool {
+	if t.deps == nil {
+		return nil
+	}
+
+	clone := make(map[entryKey]map[entryKey]bool, len(t.deps))
+	for from, deps := range t.deps {
+		clone[from] = maps.Clone(deps)
+	}
+
+	return clone
+}
+
+// goid returns the id of the current goroutine, parsed from the header of its stack trace ("goroutine 7 [running]:").
+//
+// The runtime exposes no goroutine id, factories can't be handed a build context either since GetOrCreate constructors take no arguments,
+// so this depends on the header format of runtime.Stack which is stable but undocumented. ok is false if the header can't be parsed,
+// goroutine ids start at 1 so 0 is rejected too.
+func goid() (id uint64, ok bool) {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+
+	fields := bytes.Fields(buf[:n])
+	if len(fields) < 2 || string(fields[0]) != "goroutine" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+func compareRefs(a, b EntryRef) int {
+	return cmp.Or(cmp.Compare(a.Type.String(), b.Type.String()), cmp.Compare(a.Name, b.Name))
+}
+
+func compareKeys(a, b entryKey) int {
+	return cmp.Or(cmp.Compare(a.rt.String(), b.rt.String()), cmp.Compare(a.name, b.name))
+}
@@ -0,0 +1,214 @@
+package reg
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	depA struct{ b *depB }
+	depB struct{ c *depC }
+	depC struct{ a *depA }
+)
+
+func TestDependency_Cycle(t *testing.T) {
+	r := newTestReg(t)
+
+	var newA func() (*depA, error)
+
+	newC := func() (*depC, error) {
+		a, err := GetOrCreate(newA, WithRegistry(r))
+		return &depC{a}, err
+	}
+
+	newB := func() (*depB, error) {
+		c, err := GetOrCreate(newC, WithRegistry(r))
+		return &depB{c}, err
+	}
+
+	newA = func() (*depA, error) {
+		b, err := GetOrCreate(newB, WithRegistry(r))
+		return &depA{b}, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GetOrCreate(newA, WithRegistry(r))
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrCycle) {
+			t.Fatalf("GetOrCreate() err = %v, want ErrCycle", err)
+		}
+
+		if want := "*reg.depA -> *reg.depB -> *reg.depC -> *reg.depA"; !strings.Contains(err.Error(), want) {
+			t.Fatalf("GetOrCreate() err = %v, want the cycle %s", err, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("GetOrCreate() deadlocked")
+	}
+
+	// nothing is left registered or building
+	if entries, _ := Entries(WithRegistry(r)); len(entries) != 0 {
+		t.Fatalf("Entries() = %v, want none", entries)
+	}
+
+	if len(r.building) != 0 {
+		t.Fatalf("building = %v, want none", r.building)
+	}
+}
+
+func TestDependency_SelfCycle(t *testing.T) {
+	r := newTestReg(t)
+
+	err := Provide(func(r Registry) (*depA, error) {
+		return Get[*depA](WithRegistry(r))
+	}, WithRegistry(r))
+	if !errors.Is(err, ErrCycle) {
+		t.Fatalf("Provide() err = %v, want ErrCycle", err)
+	}
+}
+
+func TestDependency_Graph(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(&depC{}, WithRegistry(r), WithName("c"))
+
+	err := Provide(func(r Registry) (*depB, error) {
+		c, err := Get[*depC](WithRegistry(r), WithName("c"))
+		return &depB{c}, err
+	}, WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Provide() error = %v", err)
+	}
+
+	_, err = GetOrCreate(func() (*depA, error) {
+		b, _ := Get[*depB](WithRegistry(r))
+		_, _ = Get[ExportedNamedTester](WithRegistry(r)) // missing
+		return &depA{b}, nil
+	}, WithRegistry(r))
+	if err != nil {
+		t.Fatalf("GetOrCreate() error = %v", err)
+	}
+
+	// lookups outside of factories are not recorded
+	_, _ = Get[*depA](WithRegistry(r))
+
+	g := r.Graph()
+	if len(g.Entries) != 3 {
+		t.Fatalf("Graph().Entries = %v, want 3", g.Entries)
+	}
+
+	var deps []string
+	for _, d := range g.Dependencies {
+		deps = append(deps, d.From.String()+" -> "+d.To.String())
+	}
+
+	want := "*reg.depA -> *reg.depB, *reg.depA -> reg.ExportedNamedTester, *reg.depB -> *reg.depC(c)"
+	if got := strings.Join(deps, ", "); got != want {
+		t.Fatalf("Graph().Dependencies = %s, want %s", got, want)
+	}
+
+	if missing := g.Missing(); len(missing) != 1 || missing[0].String() != "reg.ExportedNamedTester" {
+		t.Fatalf("Graph().Missing() = %v", missing)
+	}
+}
+
+func TestDependency_CycleAcrossGoroutines(t *testing.T) {
+	r := newTestReg(t)
+
+	// each factory waits until both are building, so each one waits for the other goroutine
+	var started sync.WaitGroup
+	started.Add(2)
+
+	newA := func() (*depA, error) {
+		started.Done()
+		started.Wait()
+
+		_, err := GetOrCreate(func() (*depB, error) { return &depB{}, nil }, WithRegistry(r))
+		return &depA{}, err
+	}
+
+	newB := func() (*depB, error) {
+		started.Done()
+		started.Wait()
+
+		_, err := GetOrCreate(func() (*depA, error) { return &depA{}, nil }, WithRegistry(r))
+		return &depB{}, err
+	}
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := GetOrCreate(newA, WithRegistry(r))
+		done <- err
+	}()
+	go func() {
+		_, err := GetOrCreate(newB, WithRegistry(r))
+		done <- err
+	}()
+
+	for range 2 {
+		select {
+		case err := <-done:
+			if !errors.Is(err, ErrCycle) {
+				t.Fatalf("GetOrCreate() err = %v, want ErrCycle", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("GetOrCreate() deadlocked")
+		}
+	}
+}
+
+func TestDependency_GraphAfterUnset(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(&depC{}, WithRegistry(r))
+	_ = Provide(func(r Registry) (*depB, error) {
+		c, err := Get[*depC](WithRegistry(r))
+		return &depB{c}, err
+	}, WithRegistry(r))
+	_ = Provide(func(r Registry) (*depA, error) {
+		b, err := Get[*depB](WithRegistry(r))
+		return &depA{b}, err
+	}, WithRegistry(r))
+
+	if err := Unset(&depB{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Unset() error = %v", err)
+	}
+
+	// the removed entry depends on nothing anymore, its dependents miss it
+	g := r.Graph()
+	if len(g.Dependencies) != 1 || g.Dependencies[0].From.String() != "*reg.depA" {
+		t.Fatalf("Graph().Dependencies = %v, want only the one of depA", g.Dependencies)
+	}
+
+	if missing := g.Missing(); len(missing) != 1 || missing[0].String() != "*reg.depB" {
+		t.Fatalf("Graph().Missing() = %v, want depB", missing)
+	}
+}
+
+func TestDependency_Goid(t *testing.T) {
+	id, ok := goid()
+	if !ok || id == 0 {
+		t.Fatalf("goid() = %d, %v, want the id of the current goroutine", id, ok)
+	}
+
+	if again, _ := goid(); again != id {
+		t.Fatalf("goid() = %d, then %d on the same goroutine", id, again)
+	}
+
+	other := make(chan uint64)
+	go func() {
+		g, _ := goid()
+		other <- g
+	}()
+
+	if g := <-other; g == id || g == 0 {
+		t.Fatalf("goid() of another goroutine = %d, want a different id than %d", g, id)
+	}
+}
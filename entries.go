@@ -0,0 +1,130 @@
+package reg
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// EntryInfo describes a single registered instance
+type EntryInfo struct {
+	Type     reflect.Type // type the instance is registered under
+	Name     string       // instance name
+	AliasOf  *EntryInfo   // canonical instance this one was registered together with (see WithAlias and SetAs), nil for canonical instances
+	Module   Module       // module that registered the instance (see Install), nil if registered directly
+	Profile  string       // profile the instance is registered for (see WithProfile), empty if registered for none
+	Primary  bool         // instance is preferred by unnamed lookups, see WithPrimary
+	Priority int          // see WithPriority
+	Len      int          // number of elements of a collection (see Append and Put), Type is its slice or map type
+}
+
+// LogValue implements slog.LogValuer
+func (t EntryInfo) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("type", t.Type.String()),
+		slog.String("name", t.Name),
+	}
+
+	if t.AliasOf != nil {
+		attrs = append(attrs, slog.Any("alias_of", *t.AliasOf))
+	}
+
+	if t.Module != nil {
+		attrs = append(attrs, slog.String("module", fmt.Sprintf("%T", t.Module)))
+	}
+
+	if t.Profile != "" {
+		attrs = append(attrs, slog.String("profile", t.Profile))
+	}
+
+	if t.Primary {
+		attrs = append(attrs, slog.Bool("primary", true))
+	}
+
+	if t.Priority != 0 {
+		attrs = append(attrs, slog.Int("priority", t.Priority))
+	}
+
+	if t.Len != 0 {
+		attrs = append(attrs, slog.Int("len", t.Len))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Entries describes all registered instances (including the ones registered for profiles) and collections, sorted by type, name and profile.
+//
+// It supports the same options as [GetAll] and is meant for introspection (logging, debugging, tooling).
+func Entries(opts ...Option) ([]EntryInfo, error) {
+	r, err := acquire(OpGetAll, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkGetAllOpts(r.callOptions); err != nil {
+		r.observe(OpGetAll, nil, err)
+		return nil, err
+	}
+
+	return entries(r), nil
+}
+
+// entries describes the instances and collections of r, filtered by callopts from r. Caller must hold the lock and the call options
+func entries(r *registry) []EntryInfo {
+	ns := r.namespace()
+
+	stub := filtered(r)
+
+	var out []EntryInfo
+	for rt, instances := range stub.store {
+		for name := range instances {
+			out = append(out, r.entryInfo(ns, entryKey{rt, joinName(ns, name)}))
+		}
+	}
+
+	for key, c := range stub.collections {
+		out = append(out, EntryInfo{Type: key.rt, Name: key.name, Len: c.len()})
+	}
+
+	for key := range stub.profiles {
+		for _, profile := range stub.profileNames(key) {
+			out = append(out, EntryInfo{Type: key.rt, Name: key.name, Profile: profile})
+		}
+	}
+
+	slices.SortFunc(out, func(a, b EntryInfo) int {
+		return cmp.Or(
+			cmp.Compare(a.Type.String(), b.Type.String()),
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Profile, b.Profile),
+		)
+	})
+
+	return out
+}
+
+// entryInfo describes the instance stored under key, with names relative to ns
+func (t *registry) entryInfo(ns string, key entryKey) EntryInfo {
+	name, _ := relName(ns, key.name)
+	info := EntryInfo{Type: key.rt, Name: name}
+
+	m := t.meta[key]
+	if m == nil {
+		return info
+	}
+
+	if m.canonical != nil {
+		canonical := t.entryInfo(ns, *m.canonical)
+		info.AliasOf = &canonical
+	}
+
+	info.Module = m.owner
+	info.Primary = m.primary
+	info.Priority = m.priority
+
+	return info
+}
@@ -0,0 +1,38 @@
+package reg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntries_SortedAndFiltered(t *testing.T) {
+	r := newTestReg(t)
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r).WithName("b"))
+	MustSet(ExportedNamedTester{ID: 2}, WithRegistry(r).WithName("a"))
+	MustSet(42, WithRegistry(r))
+
+	got, err := Entries(WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Entries error = %v", err)
+	}
+
+	rt := reflect.TypeFor[ExportedNamedTester]()
+	want := []EntryInfo{
+		{Type: reflect.TypeFor[int](), Name: ""},
+		{Type: rt, Name: "a"},
+		{Type: rt, Name: "b"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Entries = %v, want %v", got, want)
+	}
+
+	got, err = Entries(WithRegistry(r).WithName("a"))
+	if err != nil {
+		t.Fatalf("Entries WithName error = %v", err)
+	}
+
+	if len(got) != 1 || got[0].Name != "a" {
+		t.Fatalf("Entries WithName = %v, want only 'a'", got)
+	}
+}
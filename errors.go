@@ -1,6 +1,9 @@
 package reg
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // To check for errors use errors.Is(err), don't use direct comparison(==) as they are wrapped
 var (
@@ -11,4 +14,39 @@ var (
 	ErrBadOption           = fmt.Errorf("bad option")
 	ErrAccessibilityTooLow = fmt.Errorf("accessibility too low")
 	ErrNamednessTooLow     = fmt.Errorf("namedness too low")
+	ErrNotAssignable       = fmt.Errorf("not assignable")
+	ErrInvalidValue        = fmt.Errorf("invalid value")
+	ErrPolicyViolation     = fmt.Errorf("policy violation")
+	ErrModuleInstalled     = fmt.Errorf("module already installed")
+	ErrConcurrentChange    = fmt.Errorf("registry changed concurrently")
+	ErrProfileConflict     = fmt.Errorf("multiple active profiles provide the instance")
+	ErrCycle               = fmt.Errorf("dependency cycle")
 )
+
+// constraintErrors are the errors returned when an op violates a registry constraint
+var constraintErrors = []error{
+	ErrNotUniqueType,
+	ErrNotUniqueName,
+	ErrAccessibilityTooLow,
+	ErrNamednessTooLow,
+	ErrNotAssignable,
+	ErrInvalidValue,
+	ErrPolicyViolation,
+	ErrProfileConflict,
+	ErrCycle,
+}
+
+// violationKind returns the constraint error wrapped by err or nil if err is not a constraint violation
+func violationKind(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, kind := range constraintErrors {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package reg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mp3cko/registry/access"
+)
+
+// GraphFormat is a format [DependencyGraph.Export] renders to
+type GraphFormat int
+
+const (
+	FormatDOT     GraphFormat = iota + 1 // Graphviz DOT
+	FormatMermaid                        // Mermaid flowchart, renders in GitHub markdown
+)
+
+func (t GraphFormat) String() string {
+	switch t {
+	case FormatDOT:
+		return "dot"
+	case FormatMermaid:
+		return "mermaid"
+	default:
+		return fmt.Sprintf("GraphFormat(%d)", int(t))
+	}
+}
+
+// ExportGraph renders the dependency graph of the default registry to w, see [DependencyGraph.Export]
+func ExportGraph(w io.Writer, format GraphFormat) error {
+	return Graph().Export(w, format)
+}
+
+// Export renders the graph to w in the format. Instances are grouped by type (labeled with its accessibility relative to the package
+// which retrieved the graph using [Graph], or else the one calling Export, see [access.InfoFrom]),
+// dependencies are solid edges, aliases dashed edges to their canonical instance and missing dependencies are highlighted in red.
+//
+//	err := reg.Graph().Export(os.Stdout, reg.FormatMermaid)
+//
+// Unknown formats return ErrNotSupported.
+func (t DependencyGraph) Export(w io.Writer, format GraphFormat) error {
+	var render func(*strings.Builder, graphLayout)
+
+	switch format {
+	case FormatDOT:
+		render = renderDOT
+	case FormatMermaid:
+		render = renderMermaid
+	default:
+		return fmt.Errorf("Export format '%s': %w", format, ErrNotSupported)
+	}
+
+	var b strings.Builder
+	render(&b, t.layout(valueOrDefault(t.caller, callerPackage())))
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// graphLayout is the graph prepared for rendering, nodes are identified by their index
+type graphLayout struct {
+	groups []graphGroup
+	nodes  []graphNode
+	edges  []graphEdge
+}
+
+// graphGroup holds the nodes of a single type
+type graphGroup struct {
+	label string
+	nodes []int
+}
+
+type graphNode struct {
+	label   string
+	missing bool
+}
+
+type graphEdge struct {
+	from, to int
+	alias    bool
+}
+
+// layout assigns nodes to the entries and the missing dependencies and resolves the edges between them, accessibility is relative to the package caller
+func (t DependencyGraph) layout(caller string) graphLayout {
+	var l graphLayout
+
+	groups := map[string]int{}
+	refs := map[EntryRef]int{} // first node of each instance, entries are sorted so profile-less ones come first
+
+	add := func(ref EntryRef, label string, missing bool) int {
+		typ := ref.Type.String()
+
+		g, ok := groups[typ]
+		if !ok {
+			_, accessibility := access.InfoFrom(ref.Type, caller)
+
+			g = len(l.groups)
+			groups[typ] = g
+			l.groups = append(l.groups, graphGroup{label: typ + "\n" + accessibility.String()})
+		}
+
+		l.nodes = append(l.nodes, graphNode{label: label, missing: missing})
+		l.groups[g].nodes = append(l.groups[g].nodes, len(l.nodes)-1)
+
+		if _, ok := refs[ref]; !ok {
+			refs[ref] = len(l.nodes) - 1
+		}
+
+		return len(l.nodes) - 1
+	}
+
+	for _, e := range t.Entries {
+		add(EntryRef{e.Type, e.Name}, entryLabel(e), false)
+	}
+
+	for _, ref := range t.Missing() {
+		add(ref, valueOrDefault(ref.Name, "(default)")+"\nmissing", true)
+	}
+
+	for i, e := range t.Entries {
+		if e.AliasOf != nil {
+			if to, ok := refs[EntryRef{e.AliasOf.Type, e.AliasOf.Name}]; ok {
+				// entries and nodes share indexes
+				l.edges = append(l.edges, graphEdge{from: i, to: to, alias: true})
+			}
+		}
+	}
+
+	for _, d := range t.Dependencies {
+		from, ok := refs[d.From]
+		if !ok {
+			from = add(d.From, valueOrDefault(d.From.Name, "(default)"), false)
+		}
+
+		l.edges = append(l.edges, graphEdge{from: from, to: refs[d.To]})
+	}
+
+	return l
+}
+
+// entryLabel describes the entry inside its type group
+func entryLabel(e EntryInfo) string {
+	label := valueOrDefault(e.Name, "(default)")
+
+	var notes []string
+	if e.Profile != "" {
+		notes = append(notes, "profile "+e.Profile)
+	}
+
+	if e.Primary {
+		notes = append(notes, "primary")
+	}
+
+	if e.Priority != 0 {
+		notes = append(notes, fmt.Sprintf("priority %d", e.Priority))
+	}
+
+	if e.Len != 0 {
+		notes = append(notes, fmt.Sprintf("%d elements", e.Len))
+	}
+
+	if len(notes) > 0 {
+		label += "\n" + strings.Join(notes, ", ")
+	}
+
+	return label
+}
+
+func renderDOT(b *strings.Builder, l graphLayout) {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+	}
+
+	b.WriteString("digraph registry {\n\trankdir=LR;\n\tnode [shape=box];\n")
+
+	for i, g := range l.groups {
+		fmt.Fprintf(b, "\tsubgraph cluster_%d {\n\t\tlabel=%s;\n", i, quote(g.label))
+
+		for _, n := range g.nodes {
+			node := l.nodes[n]
+			if node.missing {
+				fmt.Fprintf(b, "\t\tn%d [label=%s, color=red, fontcolor=red, style=dashed];\n", n, quote(node.label))
+				continue
+			}
+
+			fmt.Fprintf(b, "\t\tn%d [label=%s];\n", n, quote(node.label))
+		}
+
+		b.WriteString("\t}\n")
+	}
+
+	for _, e := range l.edges {
+		if e.alias {
+			fmt.Fprintf(b, "\tn%d -> n%d [style=dashed, label=\"alias\"];\n", e.from, e.to)
+			continue
+		}
+
+		fmt.Fprintf(b, "\tn%d -> n%d;\n", e.from, e.to)
+	}
+
+	b.WriteString("}\n")
+}
+
+func renderMermaid(b *strings.Builder, l graphLayout) {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+	}
+
+	b.WriteString("flowchart LR\n")
+
+	var missing []string
+	for i, g := range l.groups {
+		fmt.Fprintf(b, "\tsubgraph t%d [%s]\n", i, quote(g.label))
+
+		for _, n := range g.nodes {
+			fmt.Fprintf(b, "\t\tn%d[%s]\n", n, quote(l.nodes[n].label))
+
+			if l.nodes[n].missing {
+				missing = append(missing, fmt.Sprintf("n%d", n))
+			}
+		}
+
+		b.WriteString("\tend\n")
+	}
+
+	for _, e := range l.edges {
+		if e.alias {
+			fmt.Fprintf(b, "\tn%d -. alias .-> n%d\n", e.from, e.to)
+			continue
+		}
+
+		fmt.Fprintf(b, "\tn%d --> n%d\n", e.from, e.to)
+	}
+
+	if len(missing) > 0 {
+		b.WriteString("\tclassDef missing stroke:#d00,stroke-dasharray:5 5,color:#d00\n")
+		fmt.Fprintf(b, "\tclass %s missing\n", strings.Join(missing, ","))
+	}
+}
@@ -0,0 +1,117 @@
+package reg
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newGraphTestReg returns a registry with an alias, a dependency and a missing dependency
+func newGraphTestReg(t *testing.T) *registry {
+	t.Helper()
+
+	r := newTestReg(t)
+
+	_ = Set(&depC{}, WithRegistry(r), WithName("c"), WithAlias("alias"))
+
+	err := Provide(func(r Registry) (*depB, error) {
+		c, _ := Get[*depC](WithRegistry(r), WithName("c"))
+		_, _ = Get[ExportedNamedTester](WithRegistry(r))
+		return &depB{c}, nil
+	}, WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Provide() error = %v", err)
+	}
+
+	return r
+}
+
+func TestExport_DOT(t *testing.T) {
+	r := newGraphTestReg(t)
+
+	var b bytes.Buffer
+	if err := r.Graph().Export(&b, FormatDOT); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	want := `digraph registry {
+	rankdir=LR;
+	node [shape=box];
+	subgraph cluster_0 {
+		label="*reg.depB\naccessible inside package";
+		n0 [label="(default)"];
+	}
+	subgraph cluster_1 {
+		label="*reg.depC\naccessible inside package";
+		n1 [label="alias"];
+		n2 [label="c"];
+	}
+	subgraph cluster_2 {
+		label="reg.ExportedNamedTester\naccessible everywhere";
+		n3 [label="(default)\nmissing", color=red, fontcolor=red, style=dashed];
+	}
+	n1 -> n2 [style=dashed, label="alias"];
+	n0 -> n2;
+	n0 -> n3;
+}
+`
+	if got := b.String(); got != want {
+		t.Fatalf("Export() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExport_Mermaid(t *testing.T) {
+	r := newGraphTestReg(t)
+
+	var b bytes.Buffer
+	if err := r.Graph().Export(&b, FormatMermaid); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"flowchart LR\n",
+		"\tsubgraph t1 [\"*reg.depC<br/>accessible inside package\"]\n\t\tn1[\"alias\"]\n\t\tn2[\"c\"]\n\tend\n",
+		"\tn1 -. alias .-> n2\n",
+		"\tn0 --> n3\n",
+		"\tclass n3 missing\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("Export() =\n%s\nmissing %q", b.String(), want)
+		}
+	}
+}
+
+func TestExport_BadFormat(t *testing.T) {
+	if err := (DependencyGraph{}).Export(&bytes.Buffer{}, GraphFormat(0)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Export() err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestExport_CallerAccessibility(t *testing.T) {
+	if g := newTestReg(t).Graph(); g.caller != thisPackage {
+		t.Fatalf("Graph().caller = %q, want %q", g.caller, thisPackage)
+	}
+
+	// *errors.errorString is unexported
+	g := DependencyGraph{Entries: []EntryInfo{{Type: reflect.TypeOf(errors.New(""))}}}
+
+	export := func() string {
+		var b bytes.Buffer
+		_ = g.Export(&b, FormatMermaid)
+
+		return b.String()
+	}
+
+	// without a caller the package calling Export is used
+	if got, want := export(), "*errors.errorString<br/>not accessible"; !strings.Contains(got, want) {
+		t.Fatalf("Export() =\n%s\nmissing %q", got, want)
+	}
+
+	// accessibility is relative to the package which retrieved the graph, not to the registry
+	g.caller = "errors"
+	if got, want := export(), "*errors.errorString<br/>accessible inside package"; !strings.Contains(got, want) {
+		t.Fatalf("Export() =\n%s\nmissing %q", got, want)
+	}
+}
@@ -0,0 +1,176 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// pendingCall is a constructor call of GetOrCreate in progress, other callers wait for it instead of constructing the same instance
+type pendingCall struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// GetOrSet returns the existing instance or registers val if there is none, loaded reports whether the instance already existed.
+//
+// The check and the registration happen atomically. Supports the same options as [Set].
+func GetOrSet[T any](val T, opts ...Option) (actual T, loaded bool, err error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpGetOrSet, rt, opts)
+	if err != nil {
+		return zeroValue[T](), false, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkMutationOpts(OpGetOrSet, r.callOptions); err != nil {
+		r.observe(OpGetOrSet, rt, err)
+		return zeroValue[T](), false, err
+	}
+
+	if existing, ok := lookup[T](r); ok {
+		return existing, true, nil
+	}
+
+	err = setEntry(r, rt, val, nil, false)
+	r.observe(OpGetOrSet, rt, err)
+
+	if err != nil {
+		return zeroValue[T](), false, err
+	}
+
+	return val, false, nil
+}
+
+// GetOrCreate returns the existing instance or registers the one built by fn if there is none.
+//
+// fn is called at most once per type and name even when multiple goroutines call GetOrCreate at the same time, the others wait for its result.
+// The registry is not locked while fn runs, so fn may use the registry to resolve its own dependencies.
+// If fn fails nothing is registered and the error is returned to every waiting caller, the next call tries again.
+// Entries fn resolves are recorded as its dependencies (see [Graph]), resolving an entry which is waiting for fn returns ErrCycle instead of deadlocking,
+// also if it is built by another goroutine. Dependencies are attributed to the factory running on the current goroutine, so entries resolved by
+// goroutines fn starts are not recorded and a cycle through them (ex. fn waits for a goroutine resolving the entry fn builds) deadlocks.
+// Attributing them costs a stack trace per factory call and per entry resolved while a factory runs.
+//
+// If an instance gets registered by other means (ex. Set) while fn runs, that instance is returned and the one built by fn is discarded.
+//
+//	db, err := GetOrCreate(func() (*sql.DB, error) {
+//		return sql.Open("postgres", dsn)
+//	}, WithName("primary"))
+//
+// Supports the same options as [Set].
+func GetOrCreate[T any](fn func() (T, error), opts ...Option) (T, error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpGetOrCreate, rt, opts)
+	if err != nil {
+		return zeroValue[T](), err
+	}
+
+	if err := checkMutationOpts(OpGetOrCreate, r.callOptions); err != nil {
+		r.observe(OpGetOrCreate, rt, err)
+		r.cleanup()
+
+		return zeroValue[T](), err
+	}
+
+	key := entryKey{rt, r.entryName()}
+
+	// fails instead of waiting for itself
+	if err := r.depend(key); err != nil {
+		r.cleanup()
+		return zeroValue[T](), err
+	}
+
+	if existing, ok := lookup[T](r); ok {
+		r.cleanup()
+		return existing, nil
+	}
+
+	if call, ok := r.inflight[key]; ok {
+		r.cleanup()
+		<-call.done
+
+		if call.err != nil {
+			return zeroValue[T](), call.err
+		}
+
+		val, _ := call.val.(T) // nil interfaces don't assert
+
+		return val, nil
+	}
+
+	call := &pendingCall{done: make(chan struct{})}
+	if r.inflight == nil {
+		r.inflight = map[entryKey]*pendingCall{}
+	}
+
+	r.inflight[key] = call
+	g := r.startBuild(key)
+
+	// keep the call options for registering the instance later
+	co := r.callOptions
+	r.cleanup()
+
+	val, err := construct(r, key, g, call, fn)
+
+	r.lock(OpGetOrCreate)
+	r.callOptions = co
+	defer r.cleanup()
+
+	r.endBuild(g)
+	delete(r.inflight, key)
+	defer close(call.done)
+
+	if err != nil {
+		call.err = err
+		return zeroValue[T](), err
+	}
+
+	if existing, ok := lookup[T](r); ok {
+		call.val = existing
+		return existing, nil
+	}
+
+	err = setEntry(r, rt, val, nil, false)
+	r.observe(OpGetOrCreate, rt, err)
+
+	if err != nil {
+		call.err = err
+		return zeroValue[T](), err
+	}
+
+	call.val = val
+
+	return val, nil
+}
+
+// construct calls fn, if it panics the waiting callers are released with an error before the panic is propagated
+func construct[T any](r *registry, key entryKey, g uint64, call *pendingCall, fn func() (T, error)) (val T, err error) {
+	completed := false
+
+	defer func() {
+		if completed {
+			return
+		}
+
+		r.lock(OpGetOrCreate)
+		r.endBuild(g)
+		delete(r.inflight, key)
+		r.unlock()
+
+		call.err = fmt.Errorf("GetOrCreate '%s' failed: constructor panicked", key.rt)
+		close(call.done)
+	}()
+
+	val, err = fn()
+	completed = true
+
+	if err != nil {
+		return val, fmt.Errorf("GetOrCreate '%s' failed: %w", key.rt, err)
+	}
+
+	return val, nil
+}
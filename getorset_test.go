@@ -0,0 +1,116 @@
+package reg
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrSet(t *testing.T) {
+	r := newTestReg(t)
+
+	got, loaded, err := GetOrSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	if err != nil || loaded || got.ID != 1 {
+		t.Fatalf("GetOrSet empty = %+v, %v, %v; want ID 1, false, nil", got, loaded, err)
+	}
+
+	got, loaded, err = GetOrSet(ExportedNamedTester{ID: 2}, WithRegistry(r))
+	if err != nil || !loaded || got.ID != 1 {
+		t.Fatalf("GetOrSet existing = %+v, %v, %v; want ID 1, true, nil", got, loaded, err)
+	}
+}
+
+func TestGetOrCreate_ConstructsOnce(t *testing.T) {
+	r := newTestReg(t)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	fn := func() (ExportedNamedTester, error) {
+		calls.Add(1)
+		<-release
+
+		// the registry is not locked while constructing
+		if _, err := Get[ExportedNamedTester](WithRegistry(r).WithName("other")); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get inside constructor err = %v, want ErrNotFound", err)
+		}
+
+		return ExportedNamedTester{ID: 7}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]ExportedNamedTester, 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, err := GetOrCreate(fn, WithRegistry(r))
+			if err != nil {
+				t.Errorf("GetOrCreate error = %v", err)
+			}
+
+			results[i] = v
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("constructor called %d times, want 1", got)
+	}
+
+	for i, v := range results {
+		if v.ID != 7 {
+			t.Fatalf("result %d = %+v, want ID 7", i, v)
+		}
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 7 {
+		t.Fatalf("Get after GetOrCreate = %+v, want ID 7", got)
+	}
+}
+
+func TestGetOrCreate_ErrorsAndPanics(t *testing.T) {
+	r := newTestReg(t)
+	errBuild := errors.New("build failed")
+
+	_, err := GetOrCreate(func() (ExportedNamedTester, error) {
+		return ExportedNamedTester{}, errBuild
+	}, WithRegistry(r))
+	if !errors.Is(err, errBuild) {
+		t.Fatalf("GetOrCreate err = %v, want %v", err, errBuild)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("failed constructor registered an instance, Get err = %v", err)
+	}
+
+	func() {
+		defer func() {
+			if rec := recover(); rec == nil {
+				t.Fatalf("GetOrCreate did not propagate the constructor panic")
+			}
+		}()
+
+		_, _ = GetOrCreate(func() (ExportedNamedTester, error) {
+			panic("boom")
+		}, WithRegistry(r))
+	}()
+
+	// after a failure or panic the next call constructs again
+	got, err := GetOrCreate(func() (ExportedNamedTester, error) {
+		return ExportedNamedTester{ID: 3}, nil
+	}, WithRegistry(r))
+	if err != nil || got.ID != 3 {
+		t.Fatalf("GetOrCreate retry = %+v, %v; want ID 3, nil", got, err)
+	}
+
+	if len(r.inflight) != 0 {
+		t.Fatalf("inflight calls left: %v", r.inflight)
+	}
+}
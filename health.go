@@ -0,0 +1,244 @@
+package reg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultCheckTimeout is the time a single health check gets unless set using [WithCheckTimeout]
+const DefaultCheckTimeout = 5 * time.Second
+
+// HealthChecker is implemented by instances reporting their health (ex. a database pinging its server), see [Health]
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a single health check
+type CheckResult struct {
+	Type    reflect.Type  // type the instance is registered under
+	Name    string        // instance name, relative to the namespace
+	Profile string        // profile the instance is registered for, see WithProfile
+	Latency time.Duration // time the check took, the timeout if it timed out
+	Err     error         // nil if healthy
+}
+
+// Healthy reports whether the check passed
+func (t CheckResult) Healthy() bool {
+	return t.Err == nil
+}
+
+// HealthReport is the outcome of [Health]
+type HealthReport struct {
+	Checks []CheckResult // sorted by type, name and profile
+	Err    error         // set if the checks couldn't run (ex. unsupported options)
+}
+
+// Healthy reports whether the checks could run and all of them passed
+func (t HealthReport) Healthy() bool {
+	if t.Err != nil {
+		return false
+	}
+
+	for _, c := range t.Checks {
+		if !c.Healthy() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Health runs the checks of every instance implementing [HealthChecker] in parallel, each with its own timeout (see [WithCheckTimeout]).
+//
+// Instances registered under multiple types or names (see [SetAs] and [WithAlias]) are checked once, instances registered for profiles
+// only if the profile is active, in which case they are checked instead of the profile-less instance they shadow (like [Get] resolves them).
+// The registry is not locked while the checks run. A check ignoring ctx is reported as timed out and left running in the background.
+//
+// It supports the same options as [GetAll] to select the instances (ex. WithNamespace or WithNamePattern), plus WithCheckTimeout.
+//
+//	report := Health(ctx, WithNamespace("db"))
+//	if !report.Healthy() {
+//		...
+//	}
+func Health(ctx context.Context, opts ...Option) HealthReport {
+	r, err := acquire(OpHealth, nil, opts)
+	if err != nil {
+		return HealthReport{Err: err}
+	}
+
+	if err := checkGetAllOpts(r.callOptions); err != nil {
+		r.observe(OpHealth, nil, err)
+		r.cleanup()
+		return HealthReport{Err: err}
+	}
+
+	timeout := valueOrDefault(r.callOptions.checkTimeout, DefaultCheckTimeout)
+	targets := healthTargets(r)
+	r.cleanup()
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			targets[i].result.Latency, targets[i].result.Err = runCheck(ctx, targets[i].checker, timeout)
+		}()
+	}
+
+	wg.Wait()
+
+	report := HealthReport{Checks: make([]CheckResult, len(targets))}
+	for i, target := range targets {
+		report.Checks[i] = target.result
+	}
+
+	return report
+}
+
+// healthTarget is an instance to check
+type healthTarget struct {
+	checker HealthChecker
+	result  CheckResult
+}
+
+// healthTargets returns the instances to check sorted, filtered by callopts from r. Caller must hold the lock and the call options
+func healthTargets(r *registry) []healthTarget {
+	ns := r.namespace()
+
+	var targets []healthTarget
+	for _, e := range entries(r) {
+		key := entryKey{e.Type, joinName(ns, e.Name)}
+
+		var val any
+		switch {
+		case e.Len != 0:
+			continue
+		case e.Profile != "":
+			if !slices.Contains(r.config.profiles, e.Profile) {
+				continue
+			}
+
+			val = r.profiles[key][e.Profile]
+		default:
+			// aliases are checked through their canonical instance, shadowed instances are not resolved by Get
+			if m := r.meta[key]; m != nil && m.canonical != nil || len(r.activeProfiles(key)) > 0 {
+				continue
+			}
+
+			val = r.store[key.rt][key.name]
+		}
+
+		if checker, ok := val.(HealthChecker); ok && !isNil(checker) {
+			targets = append(targets, healthTarget{checker, CheckResult{Type: e.Type, Name: e.Name, Profile: e.Profile}})
+		}
+	}
+
+	return targets
+}
+
+// runCheck runs a single check with the timeout, returning its latency and error
+func runCheck(ctx context.Context, checker HealthChecker, timeout time.Duration) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	start := time.Now()
+
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("check panicked: %v", p)
+			}
+		}()
+
+		done <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return time.Since(start), err
+	case <-ctx.Done():
+		return time.Since(start), fmt.Errorf("check timed out: %w", ctx.Err())
+	}
+}
+
+// HealthHandler returns an http.Handler serving [Health] with the options, for liveness and readiness endpoints.
+// It responds with 200 if all checks pass and 503 otherwise, with a JSON body:
+//
+//	{"status":"down","checks":[{"type":"*app.DB","name":"primary","status":"down","latency":"1.2ms"}]}
+//
+// Errors are left out since they may leak internals (ex. addresses or credentials) to whoever can reach the endpoint, see [HealthHandlerWithErrors].
+// Select the checks for each endpoint using options:
+//
+//	mux.Handle("/livez", HealthHandler(WithNamespace("live")))
+//	mux.Handle("/readyz", HealthHandler(WithCheckTimeout(time.Second)))
+func HealthHandler(opts ...Option) http.Handler {
+	return healthHandler(false, opts)
+}
+
+// HealthHandlerWithErrors is the variant of [HealthHandler] reporting the errors of the failed checks, use it for internal endpoints only:
+//
+//	{"status":"down","checks":[{"type":"*app.DB","name":"primary","status":"down","latency":"1.2ms","error":"dial tcp: connection refused"}]}
+func HealthHandlerWithErrors(opts ...Option) http.Handler {
+	return healthHandler(true, opts)
+}
+
+// healthHandler serves Health with the options, including the error messages if withErrors is set
+func healthHandler(withErrors bool, opts []Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := Health(req.Context(), opts...)
+
+		type check struct {
+			Type    string `json:"type"`
+			Name    string `json:"name,omitempty"`
+			Profile string `json:"profile,omitempty"`
+			Status  string `json:"status"`
+			Latency string `json:"latency"`
+			Error   string `json:"error,omitempty"`
+		}
+
+		body := struct {
+			Status string  `json:"status"`
+			Error  string  `json:"error,omitempty"`
+			Checks []check `json:"checks"`
+		}{Status: healthStatus(report.Healthy()), Checks: []check{}}
+
+		if report.Err != nil && withErrors {
+			body.Error = report.Err.Error()
+		}
+
+		for _, c := range report.Checks {
+			out := check{Type: c.Type.String(), Name: c.Name, Profile: c.Profile, Status: healthStatus(c.Healthy()), Latency: c.Latency.String()}
+			if c.Err != nil && withErrors {
+				out.Error = c.Err.Error()
+			}
+
+			body.Checks = append(body.Checks, out)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		if report.Healthy() {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+func healthStatus(healthy bool) string {
+	if healthy {
+		return "up"
+	}
+
+	return "down"
+}
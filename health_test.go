@@ -0,0 +1,222 @@
+package reg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// checkTester is a HealthChecker counting its checks
+type checkTester struct {
+	err     error
+	release chan struct{} // if set, keep running past the ctx deadline until release is closed
+	panic   bool
+	calls   atomic.Int32
+}
+
+func (t *checkTester) Check(ctx context.Context) error {
+	t.calls.Add(1)
+
+	if t.panic {
+		panic("boom")
+	}
+
+	if t.release != nil {
+		<-ctx.Done()
+		<-t.release
+	}
+
+	return t.err
+}
+
+func TestHealth_Checks(t *testing.T) {
+	r := newTestReg(t)
+
+	up, down := &checkTester{}, &checkTester{err: errors.New("connection refused")}
+	_ = Set(up, WithRegistry(r), WithName("up"))
+	_ = Set(down, WithRegistry(r), WithName("down"))
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r))
+
+	report := Health(context.Background(), WithRegistry(r))
+	if report.Err != nil {
+		t.Fatalf("Health() error = %v", report.Err)
+	}
+
+	if report.Healthy() {
+		t.Fatalf("Health() healthy, want unhealthy")
+	}
+
+	if len(report.Checks) != 2 {
+		t.Fatalf("Health() checks = %v, want 2", report.Checks)
+	}
+
+	// sorted by name
+	if c := report.Checks[0]; c.Name != "down" || c.Healthy() || c.Type != reflect.TypeFor[*checkTester]() {
+		t.Fatalf("Checks[0] = %+v, want the failing check", c)
+	}
+
+	if c := report.Checks[1]; c.Name != "up" || !c.Healthy() {
+		t.Fatalf("Checks[1] = %+v, want the passing check", c)
+	}
+
+	if report := Health(context.Background(), WithRegistry(r), WithNamePattern(Glob("u*"))); !report.Healthy() || len(report.Checks) != 1 {
+		t.Fatalf("Health() filtered = %+v, want a single passing check", report)
+	}
+}
+
+func TestHealth_Empty(t *testing.T) {
+	r := newTestReg(t)
+
+	if report := Health(context.Background(), WithRegistry(r)); !report.Healthy() || len(report.Checks) != 0 {
+		t.Fatalf("Health() = %+v, want healthy without checks", report)
+	}
+}
+
+func TestHealth_Timeout(t *testing.T) {
+	r := newTestReg(t)
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	_ = Set(&checkTester{release: release}, WithRegistry(r))
+
+	report := Health(context.Background(), WithRegistry(r), WithCheckTimeout(10*time.Millisecond))
+	if report.Healthy() || len(report.Checks) != 1 {
+		t.Fatalf("Health() = %+v, want a single failing check", report)
+	}
+
+	if c := report.Checks[0]; !errors.Is(c.Err, context.DeadlineExceeded) || c.Latency < 10*time.Millisecond {
+		t.Fatalf("Checks[0] = %+v, want timed out", c)
+	}
+}
+
+func TestHealth_Panic(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(&checkTester{panic: true}, WithRegistry(r))
+
+	report := Health(context.Background(), WithRegistry(r))
+	if report.Healthy() || len(report.Checks) != 1 || report.Checks[0].Err == nil {
+		t.Fatalf("Health() = %+v, want the panic reported", report)
+	}
+}
+
+func TestHealth_AliasesCheckedOnce(t *testing.T) {
+	r := newTestReg(t)
+
+	c := &checkTester{}
+	as := []reflect.Type{reflect.TypeFor[HealthChecker]()}
+	if err := SetAs(c, as, WithRegistry(r), WithName("db"), WithAlias("primary")); err != nil {
+		t.Fatalf("SetAs() error = %v", err)
+	}
+
+	report := Health(context.Background(), WithRegistry(r))
+	if !report.Healthy() {
+		t.Fatalf("Health() = %+v, want healthy", report)
+	}
+
+	if got := c.calls.Load(); got != 1 || len(report.Checks) != 1 {
+		t.Fatalf("Check() called %d times, want once", got)
+	}
+}
+
+func TestHealth_Profiles(t *testing.T) {
+	r := newTestReg(t, WithActiveProfiles("prod"))
+
+	prod, dev := &checkTester{}, &checkTester{err: errors.New("down")}
+	_ = Set(prod, WithRegistry(r), WithProfile("prod"))
+	_ = Set(dev, WithRegistry(r), WithProfile("dev"))
+
+	report := Health(context.Background(), WithRegistry(r))
+	if !report.Healthy() || len(report.Checks) != 1 || report.Checks[0].Profile != "prod" {
+		t.Fatalf("Health() = %+v, want only the active profile checked", report)
+	}
+
+	if dev.calls.Load() != 0 {
+		t.Fatalf("inactive profile was checked")
+	}
+
+	// the profile-less instance is shadowed by the active profile, like Get resolves it
+	fallback := &checkTester{err: errors.New("down")}
+	if err := Set(fallback, WithRegistry(r)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if report := Health(context.Background(), WithRegistry(r)); !report.Healthy() || len(report.Checks) != 1 || fallback.calls.Load() != 0 {
+		t.Fatalf("Health() = %+v, want the shadowed instance skipped", report)
+	}
+}
+
+func TestHealth_Options(t *testing.T) {
+	r := newTestReg(t)
+
+	if report := Health(context.Background(), WithRegistry(r), WithCheckTimeout(0)); !errors.Is(report.Err, ErrBadOption) || report.Healthy() {
+		t.Fatalf("Health(WithCheckTimeout(0)) err = %v, want ErrBadOption", report.Err)
+	}
+
+	if report := Health(context.Background(), WithRegistry(r), WithUniqueName()); !errors.Is(report.Err, ErrNotSupported) {
+		t.Fatalf("Health(WithUniqueName) err = %v, want ErrNotSupported", report.Err)
+	}
+
+	if _, err := NewRegistry(WithCheckTimeout(time.Second)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("NewRegistry(WithCheckTimeout) err = %v, want ErrNotSupported", err)
+	}
+
+	if err := Set(1, WithRegistry(r), WithCheckTimeout(time.Second)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set(WithCheckTimeout) err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetAll(WithRegistry(r), WithCheckTimeout(time.Second)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetAll(WithCheckTimeout) err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(&checkTester{}, WithRegistry(r), WithNamespace("live"))
+	_ = Set(&checkTester{err: errors.New("connection refused")}, WithRegistry(r), WithNamespace("ready"))
+
+	type body struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+	}
+
+	serve := func(h http.Handler) (int, body) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		var b body
+		if err := json.Unmarshal(rec.Body.Bytes(), &b); err != nil {
+			t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+		}
+
+		return rec.Code, b
+	}
+
+	code, b := serve(HealthHandler(WithRegistry(r), WithNamespace("live")))
+	if code != http.StatusOK || b.Status != "up" || len(b.Checks) != 1 || b.Checks[0].Type != "*reg.checkTester" {
+		t.Fatalf("live = %d %+v, want 200 up", code, b)
+	}
+
+	// errors are not exposed by default
+	code, b = serve(HealthHandler(WithRegistry(r), WithNamespace("ready")))
+	if code != http.StatusServiceUnavailable || b.Status != "down" || len(b.Checks) != 1 || b.Checks[0].Status != "down" || b.Checks[0].Error != "" {
+		t.Fatalf("ready = %d %+v, want 503 down without the error", code, b)
+	}
+
+	code, b = serve(HealthHandlerWithErrors(WithRegistry(r), WithNamespace("ready")))
+	if code != http.StatusServiceUnavailable || b.Status != "down" || len(b.Checks) != 1 || b.Checks[0].Error != "connection refused" {
+		t.Fatalf("ready with errors = %d %+v, want 503 down with the error", code, b)
+	}
+}
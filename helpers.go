@@ -1,5 +1,11 @@
 package reg
 
+import (
+	"reflect"
+	"runtime"
+	"strings"
+)
+
 // valueOrDefault returns default if val == zeroValue[T]()
 func valueOrDefault[T comparable](val, def T) T {
 	if val == zeroValue[T]() {
@@ -14,3 +20,49 @@ func zeroValue[T any]() T {
 	var zero T
 	return zero
 }
+
+// thisPackage is the import path of this package
+var thisPackage = reflect.TypeFor[registry]().PkgPath()
+
+// callerPackage returns the import path of the first caller outside of this package, frames from test files count as outside
+func callerPackage() string {
+	return callerPackageSkip(0)
+}
+
+// callerPackageSkip returns the import path of the caller skip frames above the first caller outside of this package
+func callerPackageSkip(skip int) string {
+	pcs := make([]uintptr, 32+skip)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	outside := false
+	for {
+		frame, more := frames.Next()
+
+		pkg := funcPackage(frame.Function)
+		if !outside {
+			outside = pkg != thisPackage || strings.HasSuffix(frame.File, "_test.go")
+		}
+
+		if outside {
+			if skip == 0 {
+				return pkg
+			}
+			skip--
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// funcPackage returns the package import path from a fully qualified function name. ex. "full/import/path" for "full/import/path.(*Type).Method"
+func funcPackage(funcName string) string {
+	start := strings.LastIndex(funcName, "/") + 1
+
+	if dot := strings.Index(funcName[start:], "."); dot >= 0 {
+		return funcName[:start+dot]
+	}
+
+	return funcName
+}
@@ -0,0 +1,70 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Key is a typed registry key, pairing the type T with an instance name so that both are declared once:
+//
+//	var PrimaryDB = NewKey[*sql.DB]("primary")
+//
+//	err := PrimaryDB.Set(db)
+//	db, err := PrimaryDB.Get()
+//
+// An empty name uses the registry default name. Options are passed through, except that the key name takes precedence over WithName.
+type Key[T any] struct {
+	name string
+}
+
+// NewKey returns the key of the instance of type T registered under name
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// Name returns the instance name of the key, empty for the registry default name
+func (t Key[T]) Name() string {
+	return t.name
+}
+
+// Type returns the type of the key
+func (t Key[T]) Type() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+func (t Key[T]) String() string {
+	if t.name == "" {
+		return t.Type().String()
+	}
+
+	return fmt.Sprintf("%s(%s)", t.Type(), t.name)
+}
+
+// Get retrieves the instance of the key, see [Get]
+func (t Key[T]) Get(opts ...Option) (T, error) {
+	return Get[T](t.options(opts)...)
+}
+
+// MustGet retrieves the instance of the key, panics on error. See [MustGet]
+func (t Key[T]) MustGet(opts ...Option) T {
+	return MustGet[T](t.options(opts)...)
+}
+
+// Set registers the instance of the key, see [Set]
+func (t Key[T]) Set(val T, opts ...Option) error {
+	return Set(val, t.options(opts)...)
+}
+
+// Unset removes the instance of the key, see [Unset]
+func (t Key[T]) Unset(opts ...Option) error {
+	return Unset(zeroValue[T](), t.options(opts)...)
+}
+
+// options appends the key name to opts
+func (t Key[T]) options(opts []Option) []Option {
+	if t.name == "" {
+		return opts
+	}
+
+	return append(opts[:len(opts):len(opts)], WithName(t.name))
+}
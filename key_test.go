@@ -0,0 +1,46 @@
+package reg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	r := newTestReg(t)
+	primary := NewKey[ExportedNamedTester]("primary")
+	def := NewKey[ExportedNamedTester]("")
+
+	if err := primary.Set(ExportedNamedTester{ID: 1}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set error = %v", err)
+	}
+
+	if _, err := def.Get(WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("default key Get err = %v, want ErrNotFound", err)
+	}
+
+	// the key name takes precedence over WithName
+	got, err := primary.Get(WithRegistry(r), WithName("other"))
+	if err != nil || got.ID != 1 {
+		t.Fatalf("Get = %+v, %v; want ID 1", got, err)
+	}
+
+	if got := primary.MustGet(WithRegistry(r)); got.ID != 1 {
+		t.Fatalf("MustGet = %+v; want ID 1", got)
+	}
+
+	if err := primary.Unset(WithRegistry(r)); err != nil {
+		t.Fatalf("Unset error = %v", err)
+	}
+
+	if _, err := primary.Get(WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Unset err = %v, want ErrNotFound", err)
+	}
+
+	if got, want := primary.String(), "reg.ExportedNamedTester(primary)"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+
+	if got, want := def.String(), "reg.ExportedNamedTester"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package reg
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+)
+
+// defaultLogLevels are used for ops without a level configured using [WithLogLevel]
+var defaultLogLevels = map[Op]slog.Level{
+	OpSet:   slog.LevelInfo,
+	OpUnset: slog.LevelInfo,
+	OpGet:   slog.LevelDebug,
+
+	OpUnsetNamespace: slog.LevelInfo,
+	OpSwap:           slog.LevelInfo,
+	OpCompareAndSwap: slog.LevelInfo,
+	OpUpdate:         slog.LevelInfo,
+	OpGetOrSet:       slog.LevelInfo,
+	OpGetOrCreate:    slog.LevelInfo,
+	OpInstall:        slog.LevelInfo,
+	OpResolve:        slog.LevelInfo,
+	OpAppend:         slog.LevelInfo,
+	OpPut:            slog.LevelInfo,
+	OpRemove:         slog.LevelInfo,
+	OpDelete:         slog.LevelInfo,
+
+	// lookups are logged only if they fail
+	OpGetAll:      slog.LevelDebug,
+	OpGetMatching: slog.LevelDebug,
+	OpGetSlice:    slog.LevelDebug,
+	OpGetMap:      slog.LevelDebug,
+	OpHealth:      slog.LevelDebug,
+}
+
+// mutates reports whether the op modifies registry entries
+func (t Op) mutates() bool {
+	switch t {
+	case OpSet, OpUnset, OpUnsetNamespace, OpSwap, OpCompareAndSwap, OpUpdate, OpGetOrSet, OpGetOrCreate, OpInstall, OpResolve, OpAppend, OpPut, OpRemove, OpDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// logLevel returns the level used for logging op
+func (t *registryConfig) logLevel(op Op) slog.Level {
+	if level, ok := t.logLevels[op]; ok {
+		return level
+	}
+
+	return defaultLogLevels[op]
+}
+
+// log logs every mutation and failed lookup to the configured Logger
+func (t *registry) log(op Op, rt reflect.Type, name string, err error) {
+	l := t.config.logger
+	if l == nil || !op.mutates() && err == nil {
+		return
+	}
+
+	ctx := context.Background()
+
+	level := t.config.logLevel(op)
+	if !l.Enabled(ctx, level) {
+		return
+	}
+
+	msg := "registry " + string(op)
+
+	attrs := []slog.Attr{slog.String("op", string(op))}
+	if rt != nil {
+		attrs = append(attrs, slog.String("type", rt.String()))
+	}
+
+	attrs = append(attrs,
+		slog.String("name", name),
+		slog.String("caller", t.caller()),
+	)
+
+	if err != nil {
+		msg += " failed"
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	l.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// LogValue implements slog.LogValuer, it renders a compact summary of the registry.
+//
+// It doesn't take the registry lock, so the registry can be logged from callbacks running under it (ex. validators or Update).
+// The counts are the ones as of the end of the last op.
+func (t *registry) LogValue() slog.Value {
+	s := t.summary.Load()
+	if s == nil {
+		s = new(registrySummary)
+	}
+
+	return slog.GroupValue(
+		slog.Int("types", s.types),
+		slog.Int("entries", s.entries),
+		slog.String("default_name", t.config.defaultName),
+		slog.Bool("unique_types", t.config.uniqueTypes),
+		slog.Bool("unique_names", t.config.uniqueNames),
+	)
+}
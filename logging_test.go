@@ -0,0 +1,196 @@
+package reg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		m := map[string]any{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("unmarshal log line %q error = %v", line, err)
+		}
+
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func TestLogging_MutationsAndFailedLookups(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newTestReg(t, WithLogger(newTestLogger(buf)).WithLogLevel(OpUnset, slog.LevelWarn))
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r).WithName("a"))
+	MustGet[ExportedNamedTester](WithRegistry(r).WithName("a")) // successful lookups are not logged
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r).WithName("missing")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get missing err = %v, want ErrNotFound", err)
+	}
+
+	MustUnset(ExportedNamedTester{}, WithRegistry(r).WithName("a"))
+
+	lines := decodeLogLines(t, buf)
+	if len(lines) != 3 {
+		t.Fatalf("logged %d lines, want 3: %s", len(lines), buf)
+	}
+
+	want := []struct{ op, level, name string }{
+		{"set", "INFO", "a"},
+		{"get", "DEBUG", "missing"},
+		{"unset", "WARN", "a"},
+	}
+	for i, w := range want {
+		got := lines[i]
+		if got["op"] != w.op || got["level"] != w.level || got["name"] != w.name {
+			t.Fatalf("line %d = %v, want op=%s level=%s name=%s", i, got, w.op, w.level, w.name)
+		}
+
+		if got["type"] != "reg.ExportedNamedTester" || got["caller"] != thisPackage {
+			t.Fatalf("line %d = %v, missing type or caller attributes", i, got)
+		}
+	}
+
+	if _, ok := lines[1]["error"]; !ok {
+		t.Fatalf("failed lookup logged without error: %v", lines[1])
+	}
+}
+
+func TestLogging_InvalidOptions(t *testing.T) {
+	r := newTestReg(t)
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r).WithLogger(slog.Default())); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithLogger err = %v, want ErrNotSupported", err)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r).WithLogLevel(OpSet, slog.LevelInfo)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithLogLevel err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := NewRegistry(WithLogLevel(OpSet, slog.LevelInfo), WithLogLevel(OpSet, slog.LevelWarn)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("duplicate WithLogLevel err = %v, want ErrBadOption", err)
+	}
+}
+
+func TestLogging_LogValue(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newTestReg(t, WithName("def"))
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	MustSet(ExportedNamedTester{ID: 2}, WithRegistry(r).WithName("b"))
+
+	entries, err := Entries(WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Entries error = %v", err)
+	}
+
+	newTestLogger(buf).Info("state", "registry", r, "entry", entries[0])
+
+	lines := decodeLogLines(t, buf)
+	state, ok := lines[0]["registry"].(map[string]any)
+	if !ok {
+		t.Fatalf("registry not rendered as group: %v", lines[0])
+	}
+
+	if state["types"] != float64(1) || state["entries"] != float64(2) || state["default_name"] != "def" {
+		t.Fatalf("registry summary = %v", state)
+	}
+
+	entry, ok := lines[0]["entry"].(map[string]any)
+	if !ok || entry["type"] != "reg.ExportedNamedTester" || entry["name"] != "b" {
+		t.Fatalf("entry summary = %v", lines[0]["entry"])
+	}
+}
+
+func TestLogging_RejectedCallsAndLookups(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newTestReg(t, WithLogger(newTestLogger(buf)))
+
+	// rejected while applying the options
+	if err := Set(ExportedNamedTester{}, WithRegistry(r).WithCheckTimeout(0)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("Set WithCheckTimeout(0) err = %v, want ErrBadOption", err)
+	}
+
+	// rejected by the op
+	if err := Set(ExportedNamedTester{}, WithRegistry(r).WithNamePattern(Glob("*"))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithNamePattern err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetMatching[ExportedNamedTester](Glob("*"), WithRegistry(r).WithName("a")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetMatching WithName err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetSlice[int](WithRegistry(r).WithUniqueName()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetSlice WithUniqueName err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetAllOf[ExportedNamedTester](WithRegistry(r).WithName("a")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetAllOf WithName err = %v, want ErrNotSupported", err)
+	}
+
+	// rejected by the update function
+	_, err := Update(func(ExportedNamedTester, bool) (ExportedNamedTester, error) {
+		return ExportedNamedTester{}, errors.New("stale")
+	}, WithRegistry(r))
+	if err == nil {
+		t.Fatalf("Update() err = nil, want the update function error")
+	}
+
+	lines := decodeLogLines(t, buf)
+
+	want := []struct{ op, level, typ string }{
+		{"set", "INFO", "reg.ExportedNamedTester"},
+		{"set", "INFO", "reg.ExportedNamedTester"},
+		{"get_matching", "DEBUG", "reg.ExportedNamedTester"},
+		{"get_slice", "DEBUG", "[]int"},
+		{"get_all", "DEBUG", "reg.ExportedNamedTester"},
+		{"update", "INFO", "reg.ExportedNamedTester"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("logged %d lines, want %d: %s", len(lines), len(want), buf)
+	}
+
+	for i, w := range want {
+		got := lines[i]
+		if got["op"] != w.op || got["level"] != w.level || got["type"] != w.typ || got["error"] == nil {
+			t.Fatalf("line %d = %v, want failed op=%s level=%s type=%s", i, got, w.op, w.level, w.typ)
+		}
+	}
+}
+
+func TestLogging_LogValueUnderLock(t *testing.T) {
+	buf := new(bytes.Buffer)
+	r := newTestReg(t)
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+
+	// Update runs fn under the registry lock
+	_, err := Update(func(old ExportedNamedTester, _ bool) (ExportedNamedTester, error) {
+		newTestLogger(buf).Info("updating", "registry", r)
+		return old, nil
+	}, WithRegistry(r))
+	if err != nil {
+		t.Fatalf("Update error = %v", err)
+	}
+
+	state, ok := decodeLogLines(t, buf)[0]["registry"].(map[string]any)
+	if !ok || state["entries"] != float64(1) {
+		t.Fatalf("registry summary = %v", state)
+	}
+}
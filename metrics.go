@@ -0,0 +1,71 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Op identifies a registry operation
+type Op string
+
+const (
+	OpSet    Op = "set"
+	OpGet    Op = "get"
+	OpGetAll Op = "get_all"
+	OpUnset  Op = "unset"
+
+	OpGetMatching    Op = "get_matching"
+	OpUnsetNamespace Op = "unset_namespace"
+	OpSwap           Op = "swap"
+	OpCompareAndSwap Op = "compare_and_swap"
+	OpUpdate         Op = "update"
+	OpGetOrSet       Op = "get_or_set"
+	OpGetOrCreate    Op = "get_or_create"
+	OpInstall        Op = "install"
+	OpResolve        Op = "resolve"
+	OpAppend         Op = "append"
+	OpGetSlice       Op = "get_slice"
+	OpPut            Op = "put"
+	OpGetMap         Op = "get_map"
+	OpRemove         Op = "remove"
+	OpDelete         Op = "delete"
+	OpHealth         Op = "health"
+)
+
+// Metrics receives measurements of registry operations, plug it in using [WithMetrics].
+//
+// Methods are called while the registry lock is held, implementations must be safe for concurrent use and return quickly.
+// See [ExpvarMetrics] for a ready made implementation.
+type Metrics interface {
+	// Op is called after every Set, Get and Unset with the type and the resolved instance name,
+	// rt is nil for ops spanning types (ex. GetAll or UnsetNamespace, which reports the namespace as name)
+	Op(op Op, rt reflect.Type, name string)
+
+	// Lookup is called after a Get either found the instance (hit) or returned ErrNotFound (miss)
+	Lookup(rt reflect.Type, name string, hit bool)
+
+	// Violation is called when an op is rejected by a registry constraint, kind is the sentinel error (ex. ErrNotUniqueType)
+	Violation(op Op, rt reflect.Type, kind error)
+
+	// LockWait is called with the time an op spent waiting for the registry lock
+	LockWait(op Op, d time.Duration)
+}
+
+// measure reports the outcome of an op to the configured Metrics
+func (t *registry) measure(op Op, rt reflect.Type, name string, err error) {
+	m := t.config.metrics
+	if m == nil {
+		return
+	}
+
+	m.Op(op, rt, name)
+
+	if op == OpGet && (err == nil || errors.Is(err, ErrNotFound)) {
+		m.Lookup(rt, name, err == nil)
+	}
+
+	if kind := violationKind(err); kind != nil {
+		m.Violation(op, rt, kind)
+	}
+}
@@ -0,0 +1,96 @@
+package reg
+
+import (
+	"expvar"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ExpvarMetrics is a [Metrics] implementation backed by the standard library expvar package.
+//
+// Published as a single map with the following keys:
+//
+//	ops         // "<op> <type> <name>" -> count
+//	hits        // "<type> <name>" -> Get hit count
+//	misses      // "<type> <name>" -> Get miss count (ErrNotFound)
+//	hit_ratio   // hits / (hits + misses) over all types
+//	violations  // "<error kind>" -> count
+//	lock_wait   // "<op>" -> total nanoseconds spent waiting for the registry lock
+//	lock_waits  // "<op>" -> number of lock acquisitions
+type ExpvarMetrics struct {
+	root       expvar.Map
+	ops        expvar.Map
+	hits       expvar.Map
+	misses     expvar.Map
+	violations expvar.Map
+	lockWait   expvar.Map
+	lockWaits  expvar.Map
+	totalHits  expvar.Int
+	totalMiss  expvar.Int
+}
+
+// NewExpvarMetrics creates new expvar metrics and publishes them under name.
+//
+// An empty name skips publishing, the returned value is an expvar.Var itself so it can be published manually.
+// Like [expvar.Publish] it panics if name is already registered.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := new(ExpvarMetrics)
+
+	m.root.Set("ops", &m.ops)
+	m.root.Set("hits", &m.hits)
+	m.root.Set("misses", &m.misses)
+	m.root.Set("violations", &m.violations)
+	m.root.Set("lock_wait", &m.lockWait)
+	m.root.Set("lock_waits", &m.lockWaits)
+	m.root.Set("hit_ratio", expvar.Func(func() any {
+		return m.HitRatio()
+	}))
+
+	if name != "" {
+		expvar.Publish(name, m)
+	}
+
+	return m
+}
+
+// String implements expvar.Var, it returns the metrics as a JSON object
+func (t *ExpvarMetrics) String() string {
+	return t.root.String()
+}
+
+// HitRatio returns the share of Get calls that found an instance, 0 if there were none
+func (t *ExpvarMetrics) HitRatio() float64 {
+	hits, misses := t.totalHits.Value(), t.totalMiss.Value()
+	if hits+misses == 0 {
+		return 0
+	}
+
+	return float64(hits) / float64(hits+misses)
+}
+
+func (t *ExpvarMetrics) Op(op Op, rt reflect.Type, name string) {
+	t.ops.Add(fmt.Sprintf("%s %s %q", op, rt, name), 1)
+}
+
+func (t *ExpvarMetrics) Lookup(rt reflect.Type, name string, hit bool) {
+	key := fmt.Sprintf("%s %q", rt, name)
+
+	if hit {
+		t.hits.Add(key, 1)
+		t.totalHits.Add(1)
+		return
+	}
+
+	t.misses.Add(key, 1)
+	t.totalMiss.Add(1)
+}
+
+func (t *ExpvarMetrics) Violation(_ Op, _ reflect.Type, kind error) {
+	t.violations.Add(kind.Error(), 1)
+}
+
+func (t *ExpvarMetrics) LockWait(op Op, d time.Duration) {
+	t.lockWait.Add(string(op), int64(d))
+	t.lockWaits.Add(string(op), 1)
+}
@@ -0,0 +1,198 @@
+package reg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics is a Metrics implementation that records every call
+type recordingMetrics struct {
+	mu         sync.Mutex
+	ops        []string
+	hits       int
+	misses     int
+	violations []error
+	lockWaits  int
+}
+
+func (t *recordingMetrics) Op(op Op, rt reflect.Type, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ops = append(t.ops, fmt.Sprintf("%s %v %s", op, rt, name))
+}
+
+func (t *recordingMetrics) Lookup(_ reflect.Type, _ string, hit bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if hit {
+		t.hits++
+	} else {
+		t.misses++
+	}
+}
+
+func (t *recordingMetrics) Violation(_ Op, _ reflect.Type, kind error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.violations = append(t.violations, kind)
+}
+
+func (t *recordingMetrics) LockWait(Op, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.lockWaits++
+}
+
+func TestMetrics_Recording(t *testing.T) {
+	m := new(recordingMetrics)
+	r := newTestReg(t, WithMetrics(m).WithUniqueName())
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r).WithName("a"))
+
+	if err := Set(ExportedNamedTester{ID: 2}, WithRegistry(r).WithName("a")); !errors.Is(err, ErrNotUniqueName) {
+		t.Fatalf("Set duplicate err = %v, want ErrNotUniqueName", err)
+	}
+
+	MustGet[ExportedNamedTester](WithRegistry(r).WithName("a"))
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r).WithName("missing")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get missing err = %v, want ErrNotFound", err)
+	}
+
+	MustUnset(ExportedNamedTester{}, WithRegistry(r).WithName("a"))
+
+	want := []string{
+		"set reg.ExportedNamedTester a",
+		"set reg.ExportedNamedTester a",
+		"get reg.ExportedNamedTester a",
+		"get reg.ExportedNamedTester missing",
+		"unset reg.ExportedNamedTester a",
+	}
+	if !reflect.DeepEqual(m.ops, want) {
+		t.Fatalf("ops = %q, want %q", m.ops, want)
+	}
+
+	if m.hits != 1 || m.misses != 1 {
+		t.Fatalf("hits = %d misses = %d, want 1 and 1", m.hits, m.misses)
+	}
+
+	if len(m.violations) != 1 || m.violations[0] != ErrNotUniqueName {
+		t.Fatalf("violations = %v, want [%v]", m.violations, ErrNotUniqueName)
+	}
+
+	if m.lockWaits != len(want) {
+		t.Fatalf("lock waits = %d, want %d", m.lockWaits, len(want))
+	}
+}
+
+func TestMetrics_UnsetNamespace(t *testing.T) {
+	m := new(recordingMetrics)
+	r := newTestReg(t, WithMetrics(m))
+
+	_ = Set(ExportedNamedTester{}, WithRegistry(r), WithName("db/a"))
+
+	if err := UnsetNamespace("db", WithRegistry(r)); err != nil {
+		t.Fatalf("UnsetNamespace() error = %v", err)
+	}
+
+	if err := UnsetNamespace("db", WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("UnsetNamespace() again err = %v, want ErrNotFound", err)
+	}
+
+	want := []string{
+		"set reg.ExportedNamedTester db/a",
+		"unset_namespace <nil> db",
+		"unset_namespace <nil> db",
+	}
+	if !reflect.DeepEqual(m.ops, want) {
+		t.Fatalf("ops = %q, want %q", m.ops, want)
+	}
+}
+
+func TestMetrics_Install(t *testing.T) {
+	m := new(recordingMetrics)
+	r := newTestReg(t, WithMetrics(m))
+
+	if err := r.Install(&testModule{name: "a"}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	if err := r.Install(&testModule{name: "b", fail: true}); err == nil {
+		t.Fatalf("Install() failing module err = nil")
+	}
+
+	if err := r.Install(nil); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("Install(nil) err = %v, want ErrBadOption", err)
+	}
+
+	want := []string{
+		"set reg.ExportedNamedTester a",
+		"install <nil> ",
+		"install <nil> ",
+		"install <nil> ",
+	}
+	if !reflect.DeepEqual(m.ops, want) {
+		t.Fatalf("ops = %q, want %q", m.ops, want)
+	}
+}
+
+func TestMetrics_WithMetricsInvalid(t *testing.T) {
+	r := newTestReg(t)
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r).WithMetrics(new(recordingMetrics))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithMetrics err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := NewRegistry(WithMetrics(nil), WithMetrics(nil)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("multiple WithMetrics err = %v, want ErrBadOption", err)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := NewExpvarMetrics("")
+	r := newTestReg(t, WithMetrics(m))
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	MustGet[ExportedNamedTester](WithRegistry(r))
+	_, _ = Get[ExportedNamedTester](WithRegistry(r).WithName("missing"))
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r).WithUniqueType())
+
+	if got := m.HitRatio(); got != 0.5 {
+		t.Fatalf("HitRatio() = %v, want 0.5", got)
+	}
+
+	var out struct {
+		Ops        map[string]int64 `json:"ops"`
+		Violations map[string]int64 `json:"violations"`
+		LockWaits  map[string]int64 `json:"lock_waits"`
+		HitRatio   float64          `json:"hit_ratio"`
+	}
+	if err := json.Unmarshal([]byte(m.String()), &out); err != nil {
+		t.Fatalf("unmarshal expvar output error = %v", err)
+	}
+
+	if got := out.Ops[`set reg.ExportedNamedTester ""`]; got != 2 {
+		t.Fatalf("set op count = %d, want 2", got)
+	}
+
+	if got := out.Violations[ErrNotUniqueType.Error()]; got != 1 {
+		t.Fatalf("violation count = %d, want 1", got)
+	}
+
+	if got := out.LockWaits[string(OpGet)]; got != 2 {
+		t.Fatalf("get lock waits = %d, want 2", got)
+	}
+
+	if out.HitRatio != 0.5 {
+		t.Fatalf("published hit_ratio = %v, want 0.5", out.HitRatio)
+	}
+}
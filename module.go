@@ -0,0 +1,197 @@
+package reg
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Module bundles related registrations into a reusable unit, installed using [Install] or [Registry.Install].
+//
+// Register must register everything through r (ex. Set(val, WithRegistry(r))), r is a staging registry
+// whose entries are only applied to the target registry if all the installed modules succeed.
+//
+// Modules are identified by equality so they must be comparable, pointers and empty structs work well:
+//
+//	type Postgres struct{ DSN string }
+//
+//	func (t *Postgres) Register(r reg.Registry) error {
+//		return reg.Set[UserStore](newPgStore(t.DSN), reg.WithRegistry(r))
+//	}
+type Module interface {
+	Register(r Registry) error
+}
+
+// Requirer is implemented by modules depending on other modules, required modules are installed first (unless they are already installed)
+type Requirer interface {
+	Requires() []Module
+}
+
+// Install installs the modules into the default registry, see [Registry.Install]
+func Install(modules ...Module) error {
+	return defReg.Load().Install(modules...)
+}
+
+// Install installs the modules and everything they require, in dependency order.
+//
+// Installation is atomic: modules register into a staging copy of the registry which replaces the entries only if all of them succeed,
+// otherwise the registry is left unchanged. If the registry changes while modules are registering (ex. a module registered something
+// without WithRegistry(r)) installation fails with ErrConcurrentChange.
+//
+// Installing a module which is already installed, or listing it twice, returns ErrModuleInstalled. Nil, non comparable modules
+// and dependency cycles return ErrBadOption. Installed modules are recorded as the owners of their entries, see [EntryInfo].
+func (t *registry) Install(modules ...Module) error {
+	t.lock(OpInstall)
+
+	order, err := t.installOrder(modules)
+	if err != nil {
+		t.observeName(OpInstall, nil, "", err)
+		t.unlock()
+
+		return err
+	}
+
+	staging := t.stage()
+	version := t.version
+	t.unlock()
+
+	for _, m := range order {
+		staging.mu.Lock()
+		staging.installing = m
+		staging.mu.Unlock()
+
+		if err = m.Register(staging); err != nil {
+			err = fmt.Errorf("Install module '%T' failed: %w", m, err)
+			break
+		}
+
+		staging.modules = append(staging.modules, m)
+	}
+
+	t.lock(OpInstall)
+	defer t.unlock()
+
+	if err == nil && t.version != version {
+		err = fmt.Errorf("Install failed, entries changed while modules were registering: %w", ErrConcurrentChange)
+	}
+
+	if err == nil {
+		staging.mu.Lock()
+		t.store, t.meta, t.profiles, t.collections = staging.store, staging.meta, staging.profiles, staging.collections
+		t.pending, t.deps, t.modules = staging.pending, staging.deps, staging.modules
+		t.version++
+		staging.mu.Unlock()
+	}
+
+	t.observeName(OpInstall, nil, "", err)
+
+	return err
+}
+
+// Modules returns the installed modules, in order of installation
+func (t *registry) Modules() []Module {
+	t.mu.Lock()
+	defer t.unlock()
+
+	return slices.Clone(t.modules)
+}
+
+// installOrder validates the modules and returns them together with their requirements in dependency order, skipping installed ones. Caller must hold the lock
+func (t *registry) installOrder(modules []Module) ([]Module, error) {
+	var (
+		order    []Module
+		visiting []Module
+	)
+
+	var visit func(m Module) error
+	visit = func(m Module) error {
+		if m == nil {
+			return fmt.Errorf("Install nil module: %w", ErrBadOption)
+		}
+
+		if !reflect.TypeOf(m).Comparable() {
+			return fmt.Errorf("Install module '%T' is not comparable: %w", m, ErrBadOption)
+		}
+
+		if slices.Contains(t.modules, m) || slices.Contains(order, m) {
+			return nil
+		}
+
+		if slices.Contains(visiting, m) {
+			return fmt.Errorf("Install module '%T' requires itself: %w", m, ErrBadOption)
+		}
+
+		visiting = append(visiting, m)
+
+		if req, ok := m.(Requirer); ok {
+			for _, dep := range req.Requires() {
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+
+		visiting = visiting[:len(visiting)-1]
+		order = append(order, m)
+
+		return nil
+	}
+
+	for i, m := range modules {
+		if m != nil && reflect.TypeOf(m).Comparable() && (slices.Contains(t.modules, m) || slices.Contains(modules[:i], m)) {
+			return nil, fmt.Errorf("Install module '%T' failed: %w", m, ErrModuleInstalled)
+		}
+
+		if err := visit(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// stage returns a copy of the registry for modules to register into. Caller must hold the lock
+func (t *registry) stage() *registry {
+	staging := &registry{
+		store:       make(map[reflect.Type]map[string]any, len(t.store)),
+		meta:        make(map[entryKey]*entryMeta, len(t.meta)),
+		profiles:    t.cloneProfiles(),
+		collections: t.cloneCollections(),
+		deps:        t.cloneDeps(),
+		pending:     slices.Clone(t.pending),
+		resolved:    t.resolved,
+		modules:     slices.Clone(t.modules),
+		config:      t.config,
+	}
+
+	for rt, instances := range t.store {
+		staging.store[rt] = maps.Clone(instances)
+	}
+
+	for key, m := range t.meta {
+		c := *m
+		c.links = slices.Clone(m.links)
+		if m.canonical != nil {
+			canonical := *m.canonical
+			c.canonical = &canonical
+		}
+
+		staging.meta[key] = &c
+	}
+
+	return staging
+}
+
+// own records the module currently installing as the owner of key, or clears the owner if there is none. Caller must hold the lock
+func (t *registry) own(key entryKey) {
+	if t.installing != nil {
+		t.metaFor(key).owner = t.installing
+		return
+	}
+
+	if m := t.meta[key]; m != nil {
+		m.owner = nil
+		t.pruneMeta(key)
+	}
+}
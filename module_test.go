@@ -0,0 +1,146 @@
+package reg
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// testModule registers an ExportedNamedTester under its name, optionally failing or requiring other modules
+type testModule struct {
+	name     string
+	fail     bool
+	requires []Module
+	target   Registry // registers into target instead of the staging registry
+}
+
+func (t *testModule) Register(r Registry) error {
+	if t.fail {
+		return errors.New("boom")
+	}
+
+	if t.target != nil {
+		r = t.target
+	}
+
+	return Set(ExportedNamedTester{ID: len(t.name)}, WithRegistry(r), WithName(t.name))
+}
+
+func (t *testModule) Requires() []Module {
+	return t.requires
+}
+
+// uncomparableModule can't be identified by equality
+type uncomparableModule struct{ deps []Module }
+
+func (uncomparableModule) Register(Registry) error { return nil }
+
+// readerModule reads what its requirement registered while installing
+type readerModule struct {
+	dep *testModule
+	got ExportedNamedTester
+}
+
+func (t *readerModule) Register(r Registry) (err error) {
+	t.got, err = Get[ExportedNamedTester](WithRegistry(r), WithName(t.dep.name))
+	return err
+}
+
+func (t *readerModule) Requires() []Module {
+	return []Module{t.dep}
+}
+
+func TestInstall(t *testing.T) {
+	r := newTestReg(t)
+	db := &testModule{name: "db"}
+	reader := &readerModule{dep: db}
+
+	if err := r.Install(reader); err != nil {
+		t.Fatalf("Install error = %v", err)
+	}
+
+	if reader.got.ID != 2 {
+		t.Fatalf("requirement was not installed first, got %+v", reader.got)
+	}
+
+	if got := r.Modules(); !slices.Equal(got, []Module{db, reader}) {
+		t.Fatalf("Modules() = %v, want [db reader]", got)
+	}
+
+	entries, err := Entries(WithRegistry(r))
+	if err != nil || len(entries) != 1 || entries[0].Module != db {
+		t.Fatalf("Entries = %+v, %v; want one entry owned by db", entries, err)
+	}
+
+	// installed requirements are skipped
+	if err := r.Install(&testModule{name: "cache", requires: []Module{db}}); err != nil {
+		t.Fatalf("Install with installed requirement error = %v", err)
+	}
+
+	if err := r.Install(db); !errors.Is(err, ErrModuleInstalled) {
+		t.Fatalf("Install installed module err = %v, want ErrModuleInstalled", err)
+	}
+
+	// directly registered instances have no owner
+	if err := Set(ExportedNamedTester{}, WithRegistry(r), WithName("db")); err != nil {
+		t.Fatalf("Set error = %v", err)
+	}
+
+	entries, _ = Entries(WithRegistry(r), WithName("db"))
+	for _, e := range entries {
+		if e.Name == "db" && e.Module != nil {
+			t.Fatalf("replaced entry still owned by %T", e.Module)
+		}
+	}
+}
+
+func TestInstall_Atomic(t *testing.T) {
+	r := newTestReg(t)
+
+	err := r.Install(&testModule{name: "ok"}, &testModule{name: "bad", fail: true})
+	if err == nil {
+		t.Fatalf("Install with a failing module succeeded")
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r), WithName("ok")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("registration of a failed install was applied, Get err = %v", err)
+	}
+
+	if got := r.Modules(); len(got) != 0 {
+		t.Fatalf("Modules() = %v after failed install, want none", got)
+	}
+
+	// registering around the staging registry is detected
+	direct := &testModule{name: "direct", target: r}
+	if err := r.Install(direct); !errors.Is(err, ErrConcurrentChange) {
+		t.Fatalf("Install registering into the target err = %v, want ErrConcurrentChange", err)
+	}
+}
+
+func TestInstall_BadModules(t *testing.T) {
+	r := newTestReg(t)
+
+	a := &testModule{name: "a"}
+	b := &testModule{name: "b", requires: []Module{a}}
+	a.requires = []Module{b}
+	twice := &testModule{name: "twice"}
+
+	tests := []struct {
+		name    string
+		modules []Module
+		want    error
+	}{
+		{"nil", []Module{nil}, ErrBadOption},
+		{"uncomparable", []Module{uncomparableModule{}}, ErrBadOption},
+		{"cycle", []Module{a}, ErrBadOption},
+		{"listed twice", []Module{twice, twice}, ErrModuleInstalled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Install(tt.modules...); !errors.Is(err, tt.want) {
+				t.Fatalf("Install err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package reg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NamespaceView is a reusable option that scopes ops to a namespace, create it using [Namespace].
+//
+// Unlike the options returned by With* functions it is immutable, so it is safe to keep in a package level variable and share it between calls.
+type NamespaceView struct {
+	path string
+}
+
+// Namespace returns a view of the registry where instance names are slash separated paths under ns.
+//
+// Every name passed to an op using the view is transparently prefixed and [GetAll] only returns the instances inside the namespace (with relative names):
+//
+//	payments := reg.Namespace("payments")
+//
+//	reg.Set(db, payments, reg.WithName("db")) // registered as "payments/db"
+//	reg.Get[*sql.DB](payments, reg.WithName("db")) // retrieves "payments/db"
+//	reg.GetAll(payments) // returns only the instances under "payments/"
+//	reg.UnsetNamespace("payments") // removes everything under "payments/"
+//
+// The default name inside a namespace resolves to the namespace itself ("payments").
+func Namespace(ns string) NamespaceView {
+	return NamespaceView{path: strings.Trim(ns, "/")}
+}
+
+// Namespace returns a view of a namespace nested inside t
+func (t NamespaceView) Namespace(ns string) NamespaceView {
+	return NamespaceView{path: joinName(t.path, strings.Trim(ns, "/"))}
+}
+
+// Path returns the full path of the namespace
+func (t NamespaceView) Path() string {
+	return t.path
+}
+
+func (t NamespaceView) String() string {
+	return t.path
+}
+
+// apply implements Option
+func (t NamespaceView) apply(r *registry) error {
+	return withNamespaceOption(t.path).apply(r)
+}
+
+// UnsetNamespace removes all instances (of all types) whose names are inside ns, returns ErrNotFound if there are none.
+//
+// ns is relative to the namespaces set using [WithNamespace] (both at registry and call level).
+func UnsetNamespace(ns string, opts ...Option) error {
+	r, err := acquire(OpUnsetNamespace, nil, opts)
+	if err != nil {
+		return err
+	}
+
+	defer r.cleanup()
+
+	co := r.callOptions
+	if co.name != "" || co.uniqueName || co.uniqueType || co.namePattern != nil || co.aliases != nil || co.profile != "" || co.conditions != nil || co.nonNil || co.primary || co.prioritized {
+		err = fmt.Errorf("UnsetNamespace accepts only WithRegistry and WithNamespace: %w", ErrNotSupported)
+		r.observeName(OpUnsetNamespace, nil, ns, err)
+
+		return err
+	}
+
+	full := joinName(r.namespace(), strings.Trim(ns, "/"))
+	if full == "" {
+		err = fmt.Errorf("UnsetNamespace with empty namespace: %w", ErrBadOption)
+		r.observeName(OpUnsetNamespace, nil, full, err)
+
+		return err
+	}
+
+	err = unsetNamespace(r, full)
+	r.observeName(OpUnsetNamespace, nil, full, err)
+
+	return err
+}
+
+// unsetNamespace removes all instances inside the full namespace path, caller must handle mutex locking
+func unsetNamespace(r *registry, full string) error {
+	var keys []entryKey
+	for rt, instances := range r.store {
+		for name := range instances {
+			if _, ok := relName(full, name); ok {
+				keys = append(keys, entryKey{rt, name})
+			}
+		}
+	}
+
+	var removed int
+	for _, key := range keys {
+		if _, ok := r.store[key.rt][key.name]; ok {
+			r.removeEntry(key)
+			removed++
+		}
+	}
+
+	for key := range r.profiles {
+		if _, ok := relName(full, key.name); ok {
+			delete(r.profiles, key)
+			r.version++
+			removed++
+		}
+	}
+
+	for key := range r.collections {
+		if _, ok := relName(full, key.name); ok {
+			delete(r.collections, key)
+			r.version++
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		return fmt.Errorf("UnsetNamespace '%s' failed: %w", full, ErrNotFound)
+	}
+
+	return nil
+}
+
+// joinName joins non empty name parts using '/'
+func joinName(parts ...string) string {
+	var b strings.Builder
+
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('/')
+		}
+
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+// relName returns name relative to ns and whether name is inside ns at all
+func relName(ns, name string) (string, bool) {
+	switch {
+	case ns == "":
+		return name, true
+	case name == ns:
+		return "", true
+	case strings.HasPrefix(name, ns) && name[len(ns)] == '/':
+		return name[len(ns)+1:], true
+	default:
+		return "", false
+	}
+}
+
+// parentName returns the namespace the instance named name is in, "" if it isn't in any
+func parentName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[:i]
+	}
+
+	return ""
+}
@@ -0,0 +1,151 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func Test_joinName(t *testing.T) {
+	cases := []struct {
+		in   []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"", ""}, ""},
+		{[]string{"a", ""}, "a"},
+		{[]string{"", "b"}, "b"},
+		{[]string{"a", "b", "c"}, "a/b/c"},
+	}
+
+	for _, tc := range cases {
+		if got := joinName(tc.in...); got != tc.want {
+			t.Fatalf("joinName(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func Test_relName(t *testing.T) {
+	cases := []struct {
+		ns, name string
+		want     string
+		wantOk   bool
+	}{
+		{"", "a/b", "a/b", true},
+		{"a", "a", "", true},
+		{"a", "a/b", "b", true},
+		{"a", "ab", "", false},
+		{"a/b", "a/b/c/d", "c/d", true},
+		{"a/b", "a", "", false},
+	}
+
+	for _, tc := range cases {
+		got, ok := relName(tc.ns, tc.name)
+		if got != tc.want || ok != tc.wantOk {
+			t.Fatalf("relName(%q, %q) = %q, %v, want %q, %v", tc.ns, tc.name, got, ok, tc.want, tc.wantOk)
+		}
+	}
+}
+
+func TestNamespace_View(t *testing.T) {
+	r := newTestReg(t)
+	payments := Namespace("payments")
+	rt := reflect.TypeFor[ExportedNamedTester]()
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r), payments, WithName("db"))
+	MustSet(ExportedNamedTester{ID: 2}, WithRegistry(r), payments)
+	MustSet(ExportedNamedTester{ID: 3}, WithRegistry(r).WithNamespace("orders").WithName("db"))
+	MustSet(ExportedNamedTester{ID: 4}, WithRegistry(r), payments.Namespace("eu"), WithName("db"))
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r).WithName("payments/db")); got.ID != 1 {
+		t.Fatalf("Get full name = %+v, want ID 1", got)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r), payments, WithName("db")); got.ID != 1 {
+		t.Fatalf("Get in namespace = %+v, want ID 1", got)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r), payments); got.ID != 2 {
+		t.Fatalf("Get namespace default = %+v, want ID 2", got)
+	}
+
+	all := MustGetAll(WithRegistry(r), payments)
+	want := map[string]any{"db": ExportedNamedTester{ID: 1}, "": ExportedNamedTester{ID: 2}, "eu/db": ExportedNamedTester{ID: 4}}
+	if !reflect.DeepEqual(all[rt], want) {
+		t.Fatalf("GetAll in namespace = %v, want %v", all[rt], want)
+	}
+
+	matching, err := GetMatching[ExportedNamedTester](Glob("*/db"), WithRegistry(r), payments)
+	if err != nil || len(matching) != 1 || matching["eu/db"].ID != 4 {
+		t.Fatalf("GetMatching in namespace = %v, %v", matching, err)
+	}
+
+	// uniqueness is scoped to the namespace
+	if err := Set(ExportedNamedTester{ID: 5}, WithRegistry(r).WithNamespace("empty").WithUniqueType()); err != nil {
+		t.Fatalf("Set WithUniqueType in empty namespace error = %v", err)
+	}
+
+	if err := UnsetNamespace("payments", WithRegistry(r)); err != nil {
+		t.Fatalf("UnsetNamespace error = %v", err)
+	}
+
+	all = MustGetAll(WithRegistry(r))
+	if len(all[rt]) != 2 {
+		t.Fatalf("entries after UnsetNamespace = %v, want orders/db and empty", all[rt])
+	}
+
+	if err := UnsetNamespace("payments", WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("UnsetNamespace twice err = %v, want ErrNotFound", err)
+	}
+
+	if err := UnsetNamespace("", WithRegistry(r)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("UnsetNamespace empty err = %v, want ErrBadOption", err)
+	}
+
+	if err := UnsetNamespace("orders", WithRegistry(r).WithName("db")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("UnsetNamespace WithName err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestNamespace_RegistryLevel(t *testing.T) {
+	r := newTestReg(t, WithNamespace("payments").WithName("def"))
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	MustSet(ExportedNamedTester{ID: 2}, WithRegistry(r).WithNamespace("eu"))
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 1 {
+		t.Fatalf("Get default = %+v, want ID 1", got)
+	}
+
+	rt := reflect.TypeFor[ExportedNamedTester]()
+	if _, ok := r.store[rt]["payments/def"]; !ok {
+		t.Fatalf("default instance not stored under the namespace: %v", r.store[rt])
+	}
+
+	if _, ok := r.store[rt]["payments/eu/def"]; !ok {
+		t.Fatalf("nested instance not stored under the namespace: %v", r.store[rt])
+	}
+
+	all := MustGetAll(WithRegistry(r))
+	if _, ok := all[rt]["eu/def"]; !ok || len(all[rt]) != 2 {
+		t.Fatalf("GetAll names not relative to registry namespace: %v", all[rt])
+	}
+
+	// cloning keeps entries inside the destination namespace
+	dest, err := NewRegistry(WithNamespace("orders").WithName("def"), WithCloneEntries(r))
+	if err != nil {
+		t.Fatalf("NewRegistry WithCloneEntries error = %v", err)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(dest)); got.ID != 1 {
+		t.Fatalf("Get from clone = %+v, want ID 1", got)
+	}
+
+	if _, ok := dest.store[rt]["orders/eu/def"]; !ok {
+		t.Fatalf("cloned entries not moved to destination namespace: %v", dest.store[rt])
+	}
+
+	if _, err := NewRegistry(WithNamespace("a"), WithNamespace("b")); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("multiple WithNamespace err = %v, want ErrBadOption", err)
+	}
+}
@@ -2,7 +2,12 @@ package reg
 
 import (
 	"fmt"
+	"log/slog"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/mp3cko/registry/access"
 )
@@ -123,6 +128,352 @@ func withAccessibilityOption(level access.Accessibility) *option {
 	return newOptionWithPriority(f, prioritySecondHighest)
 }
 
+// WithMetrics implementation
+func withMetricsOption(m Metrics) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithMetrics used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if r.config.init.metricsSet {
+			return fmt.Errorf("multiple WithMetrics calls: %w", ErrBadOption)
+		}
+
+		r.config.metrics = m
+		r.config.init.metricsSet = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithLogger implementation
+func withLoggerOption(l *slog.Logger) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithLogger used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if r.config.init.loggerSet {
+			return fmt.Errorf("multiple WithLogger calls: %w", ErrBadOption)
+		}
+
+		r.config.logger = l
+		r.config.init.loggerSet = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithLogLevel implementation
+func withLogLevelOption(op Op, level slog.Level) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithLogLevel used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if _, ok := r.config.logLevels[op]; ok {
+			return fmt.Errorf("multiple WithLogLevel calls for op '%s': %w", op, ErrBadOption)
+		}
+
+		if r.config.logLevels == nil {
+			r.config.logLevels = map[Op]slog.Level{}
+		}
+
+		r.config.logLevels[op] = level
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithNonNil implementation
+func withNonNilOption() *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			if r.config.init.nonNilSet {
+				return fmt.Errorf("multiple WithNonNil calls: %w", ErrBadOption)
+			}
+
+			r.config.nonNil = true
+			r.config.init.nonNilSet = true
+
+			return nil
+		}
+
+		r.callOptions.nonNil = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithValidator implementation
+func withValidatorOption(rt reflect.Type, fn validateFunc) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithValidator used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if r.config.validators == nil {
+			r.config.validators = map[reflect.Type][]validateFunc{}
+		}
+
+		r.config.validators[rt] = append(r.config.validators[rt], fn)
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithPolicy implementation
+func withPolicyOption(policies ...Policy) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithPolicy used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		for _, p := range policies {
+			if p == nil {
+				return fmt.Errorf("WithPolicy nil policy: %w", ErrBadOption)
+			}
+		}
+
+		r.config.policies = append(r.config.policies, policies...)
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithCallerPackage implementation
+func withCallerPackageOption(pkg string) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithCallerPackage used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if pkg == "" {
+			return fmt.Errorf("WithCallerPackage empty package: %w", ErrBadOption)
+		}
+
+		if r.callOptions.callerSkip != 0 {
+			return fmt.Errorf("WithCallerPackage used with WithCallerSkip: %w", ErrBadOption)
+		}
+
+		r.callOptions.callerPkg = pkg
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithCallerSkip implementation
+func withCallerSkipOption(n int) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithCallerSkip used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if n < 0 {
+			return fmt.Errorf("WithCallerSkip negative skip %d: %w", n, ErrBadOption)
+		}
+
+		if r.callOptions.callerPkg != "" {
+			return fmt.Errorf("WithCallerSkip used with WithCallerPackage: %w", ErrBadOption)
+		}
+
+		r.callOptions.callerSkip = n
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithProfile implementation
+func withProfileOption(profile string) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithProfile used inside NewRegistry: %w, use WithActiveProfiles instead", ErrNotSupported)
+		}
+
+		if profile == "" {
+			return fmt.Errorf("WithProfile empty profile: %w", ErrBadOption)
+		}
+
+		r.callOptions.profile = profile
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithActiveProfiles implementation
+func withActiveProfilesOption(profiles ...string) *option {
+	f := func(r *registry) error {
+		if r.config.init.complete {
+			return fmt.Errorf("WithActiveProfiles used outside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if r.config.init.profilesSet {
+			return fmt.Errorf("multiple WithActiveProfiles calls: %w", ErrBadOption)
+		}
+
+		if slices.Contains(profiles, "") {
+			return fmt.Errorf("WithActiveProfiles empty profile: %w", ErrBadOption)
+		}
+
+		r.config.profiles = slices.Clone(profiles)
+		r.config.init.profilesSet = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithCondition implementation
+func withConditionOption(conditions ...Condition) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithCondition used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		for _, c := range conditions {
+			if c == nil {
+				return fmt.Errorf("WithCondition nil condition: %w", ErrBadOption)
+			}
+		}
+
+		r.callOptions.conditions = append(r.callOptions.conditions, conditions...)
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithPrimary implementation
+func withPrimaryOption() *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithPrimary used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		r.callOptions.primary = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithPriority implementation
+func withPriorityOption(n int) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithPriority used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if r.callOptions.prioritized {
+			return fmt.Errorf("multiple WithPriority calls: %w", ErrBadOption)
+		}
+
+		r.callOptions.priority = n
+		r.callOptions.prioritized = true
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithCheckTimeout implementation
+func withCheckTimeoutOption(d time.Duration) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithCheckTimeout used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		if d <= 0 {
+			return fmt.Errorf("WithCheckTimeout non positive timeout %s: %w", d, ErrBadOption)
+		}
+
+		r.callOptions.checkTimeout = d
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithAlias implementation
+func withAliasOption(names ...string) *option {
+	f := func(r *registry) error {
+		if !r.config.init.complete {
+			return fmt.Errorf("WithAlias used inside NewRegistry: %w", ErrNotSupported)
+		}
+
+		r.callOptions.aliases = append(r.callOptions.aliases, names...)
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithNamePattern implementation
+func withNamePatternOption(p NamePattern) *option {
+	f := func(r *registry) error {
+		if err := p.validate(); err != nil {
+			return err
+		}
+
+		if r.callOptions.namePattern != nil {
+			return fmt.Errorf("multiple WithNamePattern calls: %w", ErrBadOption)
+		}
+
+		r.callOptions.namePattern = &p
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
+// WithNamespace implementation
+func withNamespaceOption(ns string) *option {
+	f := func(r *registry) error {
+		ns := strings.Trim(ns, "/")
+
+		if !r.config.init.complete {
+			if r.config.namespace != "" {
+				return fmt.Errorf("WithNamespace called multiple times: %w", ErrBadOption)
+			}
+
+			r.config.namespace = ns
+
+			return nil
+		}
+
+		r.callOptions.namespace = joinName(r.callOptions.namespace, ns)
+
+		return nil
+	}
+
+	return newOption(f)
+}
+
 // WithCloneEntries implementation
 func withCloneEntriesOption(src *registry) *option {
 	f := func(dest *registry) error {
@@ -160,7 +511,7 @@ func withCloneConfigOption(src *registry) *option {
 		src.mu.Lock()
 		defer src.mu.Unlock()
 
-		dest.config = src.config.clone()
+		inheritConfig(dest, src)
 
 		return nil
 	}
@@ -182,7 +533,7 @@ func withCloneRegistryOption(src *registry) *option {
 		src.mu.Lock()
 		defer src.mu.Unlock()
 
-		dest.config = src.config.clone()
+		inheritConfig(dest, src)
 		cloneEntries(src, dest)
 		// dest.config.init.clonedRegistry = true
 
@@ -192,6 +543,69 @@ func withCloneRegistryOption(src *registry) *option {
 	return newOptionWithPriority(f, priorityLowest)
 }
 
+// inheritConfig replaces the config of dest with a copy of the src config, merging the settings dest configured itself over it.
+// Settings of dest win except for the default name (see WithCloneConfig), validators and policies of both apply.
+func inheritConfig(dest, src *registry) {
+	cfg := src.config.clone()
+	own := dest.config
+
+	if own.init.uniqueTypesSet {
+		cfg.uniqueTypes, cfg.init.uniqueTypesSet = own.uniqueTypes, true
+	}
+
+	if own.init.uniqueNamesSet {
+		cfg.uniqueNames, cfg.init.uniqueNamesSet = own.uniqueNames, true
+	}
+
+	if own.init.accessibilitySet {
+		cfg.accessibility, cfg.init.accessibilitySet = own.accessibility, true
+	}
+
+	if own.init.namednessSet {
+		cfg.namedness, cfg.init.namednessSet = own.namedness, true
+	}
+
+	if own.init.metricsSet {
+		cfg.metrics, cfg.init.metricsSet = own.metrics, true
+	}
+
+	if own.init.loggerSet {
+		cfg.logger, cfg.init.loggerSet = own.logger, true
+	}
+
+	if own.init.nonNilSet {
+		cfg.nonNil, cfg.init.nonNilSet = own.nonNil, true
+	}
+
+	if own.init.profilesSet {
+		cfg.profiles, cfg.init.profilesSet = own.profiles, true
+	}
+
+	if own.namespace != "" {
+		cfg.namespace = own.namespace
+	}
+
+	if len(own.logLevels) > 0 {
+		if cfg.logLevels == nil {
+			cfg.logLevels = make(map[Op]slog.Level, len(own.logLevels))
+		}
+
+		maps.Copy(cfg.logLevels, own.logLevels)
+	}
+
+	for rt, validators := range own.validators {
+		if cfg.validators == nil {
+			cfg.validators = map[reflect.Type][]validateFunc{}
+		}
+
+		cfg.validators[rt] = append(cfg.validators[rt], validators...)
+	}
+
+	cfg.policies = append(cfg.policies, own.policies...)
+
+	dest.config = cfg
+}
+
 func cloneEntries(src, dest *registry) {
 	if dest.store == nil {
 		dest.store = make(map[reflect.Type]map[string]any, len(src.store))
@@ -205,23 +619,62 @@ func cloneEntries(src, dest *registry) {
 	uniqueType := opts.uniqueType
 	nameFilter := opts.name
 
-	for rt, instances := range src.store {
+	// inside NewRegistry the pattern is passed to the registry being constructed
+	pattern := opts.namePattern
+	if dest.callOptions != nil && dest.callOptions.namePattern != nil {
+		pattern = dest.callOptions.namePattern
+	}
+
+	// names are cloned relative to the source namespace into the destination namespace
+	srcNamespace := joinName(src.config.namespace, opts.namespace)
+	destNamespace := dest.config.namespace
+
+	// skipType reports whether instances of rt are filtered out
+	skipType := func(rt reflect.Type) bool {
 		if int(namednessOption)+int(accessibilityOption) > 0 {
-			rtNamedness, rtAccessibility := access.Info(rt)
+			rtNamedness, rtAccessibility := access.InfoFrom(rt, src.caller())
+			rtNamedness = refineNamedness(rt, rtNamedness, namednessOption)
 
 			if accessibilityOption != 0 &&
 				rtAccessibility != accessibilityOption ||
 				namednessOption != 0 &&
 					rtNamedness != namednessOption {
-				continue
+				return true
 			}
 
 		}
-		nInstances := len(instances)
-		if nInstances > 1 && uniqueType {
+
+		return uniqueType && src.scopedLen(rt) > 1
+	}
+
+	// destName maps the source instance name to its name inside dest, reporting whether the instance is cloned at all
+	destName := func(name string) (string, bool) {
+		name, ok := relName(srcNamespace, name)
+		if !ok {
+			return "", false
+		}
+
+		if pattern != nil && !pattern.Match(name) {
+			return "", false
+		}
+
+		if nameFilter != "" {
+			return joinName(destNamespace, name), name == nameFilter
+		}
+
+		if dest.config.defaultName != src.config.defaultName && name == src.config.defaultName {
+			return joinName(destNamespace, dest.config.defaultName), true
+		}
+
+		return joinName(destNamespace, name), true
+	}
+
+	for rt, instances := range src.store {
+		if skipType(rt) {
 			continue
 		}
 
+		nInstances := src.scopedLen(rt)
 		if nameFilter != "" {
 			nInstances = 1
 		}
@@ -232,24 +685,56 @@ func cloneEntries(src, dest *registry) {
 		}
 
 		for name, instance := range instances {
-			if nameFilter != "" {
-				if name == nameFilter {
-					dest.store[rt][name] = instance
-				}
-
+			to, ok := destName(name)
+			if !ok {
 				continue
 			}
 
-			if dest.config.defaultName != src.config.defaultName && name == src.config.defaultName {
-				dest.store[rt][dest.config.defaultName] = instance
+			dest.store[rt][to] = instance
 
-				continue
+			// primary and priority are kept, other metadata refers to instances which may not be cloned
+			if m := src.meta[entryKey{rt, name}]; m != nil && (m.primary || m.prioritized) {
+				dm := dest.metaFor(entryKey{rt, to})
+				dm.primary, dm.priority, dm.prioritized = m.primary, m.priority, m.prioritized
 			}
+		}
 
-			dest.store[rt][name] = instance
+		if len(dest.store[rt]) == 0 {
+			delete(dest.store, rt)
 		}
 	}
 
+	for key, instances := range src.profiles {
+		if skipType(key.rt) {
+			continue
+		}
+
+		name, ok := destName(key.name)
+		if !ok {
+			continue
+		}
+
+		if dest.profiles == nil {
+			dest.profiles = map[entryKey]map[string]any{}
+		}
+
+		destKey := entryKey{key.rt, name}
+		if dest.profiles[destKey] == nil {
+			dest.profiles[destKey] = make(map[string]any, len(instances))
+		}
+
+		maps.Copy(dest.profiles[destKey], instances)
+	}
+
+	for key, c := range src.collections {
+		if skipType(key.rt) {
+			continue
+		}
+
+		if name, ok := destName(key.name); ok {
+			dest.collectionFor(entryKey{key.rt, name}).merge(c)
+		}
+	}
 }
 
 func newOption(o optionFunc) *option {
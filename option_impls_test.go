@@ -1,6 +1,8 @@
 package reg
 
 import (
+	"errors"
+	"log/slog"
 	"reflect"
 	"testing"
 
@@ -263,3 +265,46 @@ func TestOptionImpls_CloneRegistry_InvalidCombos(t *testing.T) {
 	}
 
 }
+
+func TestOptionImpls_CloneRegistry_KeepsOwnConfig(t *testing.T) {
+	srcLogger, destLogger := slog.New(slog.DiscardHandler), slog.New(slog.DiscardHandler)
+	src := newTestReg(t, WithPolicy(ForbidAnyAndError()), WithLogger(srcLogger), WithLogLevel(OpSet, slog.LevelWarn))
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(src))
+
+	dest := newTestReg(t,
+		WithCloneRegistry(src),
+		WithPolicy(ForbidStructValues()),
+		WithLogger(destLogger),
+		WithLogLevel(OpGet, slog.LevelWarn),
+		WithNonNil(),
+		WithValidator(func(*ExportedNamedTester) error { return errors.New("rejected") }),
+		WithNamespace("ns"),
+	)
+
+	// policies of both apply
+	if err := Set[any](1, WithRegistry(dest)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set any err = %v, want ErrPolicyViolation", err)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(dest)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set struct err = %v, want ErrPolicyViolation", err)
+	}
+
+	if err := Set(&ExportedNamedTester{}, WithRegistry(dest)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set validated err = %v, want ErrInvalidValue", err)
+	}
+
+	if err := Set[*int](nil, WithRegistry(dest)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set nil err = %v, want ErrInvalidValue", err)
+	}
+
+	cfg := dest.config
+	if cfg.logger != destLogger || cfg.logLevel(OpSet) != slog.LevelWarn || cfg.logLevel(OpGet) != slog.LevelWarn {
+		t.Fatalf("logger settings not merged: %+v", cfg)
+	}
+
+	// entries are cloned into the namespace of dest
+	if cfg.namespace != "ns" || dest.store[reflect.TypeFor[ExportedNamedTester]()]["ns"] != (ExportedNamedTester{ID: 1}) {
+		t.Fatalf("namespace not kept: %q %v", cfg.namespace, dest.store)
+	}
+}
@@ -102,7 +102,6 @@ func applyOptions(r *registry, opts ...*option) (err error) {
 
 	for _, opt := range opts {
 		if err = opt.apply(r); err != nil {
-			r.dropCallOpts()
 			return err
 		}
 
@@ -111,13 +110,17 @@ func applyOptions(r *registry, opts ...*option) (err error) {
 	return
 }
 
-// unwrapOptions unwrap []Option interface to []*option from concrete []*optionsBuilder
+// unwrapOptions unwrap []Option interface to []*option from concrete []*optionsBuilder, other Option implementations (ex. NamespaceView) are wrapped as is
 func unwrapOptions(opts []Option) []*option {
 	unwrapped := make([]*option, 0, len(opts))
 
 	for _, opt := range opts {
-		ob := opt.(*optionsBuilder)
-		unwrapped = append(unwrapped, ob.o...)
+		switch o := opt.(type) {
+		case *optionsBuilder:
+			unwrapped = append(unwrapped, o.o...)
+		default:
+			unwrapped = append(unwrapped, newOption(o.apply))
+		}
 	}
 
 	return unwrapped
@@ -1,6 +1,11 @@
 package reg
 
-import "github.com/mp3cko/registry/access"
+import (
+	"log/slog"
+	"time"
+
+	"github.com/mp3cko/registry/access"
+)
 
 // WithRegistry use the given registry for a single op
 //
@@ -76,6 +81,275 @@ func (t *optionsBuilder) WithName(n string) *optionsBuilder {
 
 }
 
+// WithNonNil rejects nil values (nil interfaces, pointers, maps, funcs and chans) with ErrInvalidValue
+//
+// Valid:
+//
+//	NewRegistry(WithNonNil()) // no nil value can be registered in the registry
+//
+//	Set(val, WithNonNil()) // fails if val is nil
+//
+// Invalid:
+//
+//	Get[T](WithNonNil()) // returns ErrNotSupported, same for GetAll, GetMatching, GetSlice, Unset and the other ops not registering values
+func (t *optionsBuilder) WithNonNil() *optionsBuilder {
+	return t.and(withNonNilOption())
+}
+
+// WithPolicy adds type level rules to the registry, they are checked (in order of appearance) for every type an instance is registered under.
+// Violations are returned wrapped in ErrPolicyViolation. Policies are copied by [WithCloneConfig] and [WithCloneRegistry].
+//
+// Valid:
+//
+//	NewRegistry(WithPolicy(RequireInterfaceKeys(), ForbidAnyAndError())) // only interfaces, but not any or error
+//
+//	NewRegistry(WithPolicy(ForbidStructValues(), AllowPackages("github.com/acme/...")))
+//
+// Invalid:
+//
+//	Set(val, WithPolicy(p)) // returns ErrNotSupported, same for all other ops
+func (t *optionsBuilder) WithPolicy(policies ...Policy) *optionsBuilder {
+	return t.and(withPolicyOption(policies...))
+}
+
+// WithCallerPackage computes accessibility relative to the package pkg (an import path) instead of the caller's package.
+// Useful for wrappers and tests where the calling package isn't the one that should retrieve the instance.
+//
+// Valid:
+//
+//	Set(val, WithAccessibility(access.AccessibleInsidePackage), WithCallerPackage("example.com/app/internal/db"))
+//
+// Invalid:
+//
+//	NewRegistry(WithCallerPackage(pkg)) // returns ErrNotSupported
+//
+//	Set(val, WithCallerPackage(""))     // returns ErrBadOption
+//
+//	Set(val, WithCallerPackage(pkg), WithCallerSkip(1)) // returns ErrBadOption
+func (t *optionsBuilder) WithCallerPackage(pkg string) *optionsBuilder {
+	return t.and(withCallerPackageOption(pkg))
+}
+
+// WithCallerSkip computes accessibility relative to the package n frames above the first caller outside of the registry.
+// Wrappers pass 1 so that their own callers are used:
+//
+//	func MustSet[T any](val T) {
+//		if err := reg.Set(val, reg.WithCallerSkip(1)); err != nil {
+//			panic(err)
+//		}
+//	}
+//
+// Valid:
+//
+//	Set(val, WithCallerSkip(1))
+//
+// Invalid:
+//
+//	NewRegistry(WithCallerSkip(1)) // returns ErrNotSupported
+//
+//	Set(val, WithCallerSkip(-1))   // returns ErrBadOption
+func (t *optionsBuilder) WithCallerSkip(n int) *optionsBuilder {
+	return t.and(withCallerSkipOption(n))
+}
+
+// WithProfile registers the instance for a profile (ex. "dev", "test", "prod") instead of unconditionally.
+// [Get] resolves the instance registered for one of the registry's active profiles (see [WithActiveProfiles]), falling back to the profile-less instance.
+//
+// Valid:
+//
+//	Set(memStore, WithProfile("dev"))      // used if "dev" is active
+//
+//	Set(pgStore, WithProfile("prod"))      // used if "prod" is active
+//
+//	Get[Store](WithProfile("dev"))         // returns the instance registered for "dev", regardless of the active profiles
+//
+//	Unset[Store](nil, WithProfile("dev"))  // unsets only the instance registered for "dev"
+//
+// Invalid:
+//
+//	NewRegistry(WithProfile("dev"))        // returns ErrNotSupported, use WithActiveProfiles
+//
+//	Set(val, WithProfile(""))              // returns ErrBadOption
+//
+//	Swap(val, WithProfile("dev"))          // returns ErrNotSupported, same for SetAs, WithAlias, GetAll, GetMatching and the other ops
+func (t *optionsBuilder) WithProfile(profile string) *optionsBuilder {
+	return t.and(withProfileOption(profile))
+}
+
+// WithActiveProfiles activates the profiles, [Get] resolves instances registered for them using [WithProfile] before the profile-less ones.
+// If multiple active profiles provide an instance Get returns ErrProfileConflict.
+//
+// Active profiles are copied by [WithCloneConfig] and [WithCloneRegistry] unless the new registry sets its own, so tests can switch them:
+//
+//	testReg, err := NewRegistry(WithCloneRegistry(appReg), WithActiveProfiles("test"))
+//
+// Valid:
+//
+//	NewRegistry(WithActiveProfiles("prod"))
+//
+//	NewRegistry(WithActiveProfiles("dev", "local"))
+//
+// Invalid:
+//
+//	NewRegistry(WithActiveProfiles("dev"), WithActiveProfiles("test")) // returns ErrBadOption, same for empty profile names
+//
+//	Get[T](WithActiveProfiles("dev")) // returns ErrNotSupported, use WithProfile. Same for all other ops
+func (t *optionsBuilder) WithActiveProfiles(profiles ...string) *optionsBuilder {
+	return t.and(withActiveProfilesOption(profiles...))
+}
+
+// WithCondition guards the registration by conditions (ex. [OnMissing], [OnPresent], [OnEnv]), the instance is registered only if all of them hold.
+//
+// Conditions are evaluated by [Resolve] in order of registration, or immediately if the registry was already resolved.
+// This lets library modules register defaults which applications override, no matter the order of registration:
+//
+//	// library
+//	Set[Logger](defaultLogger{}, WithCondition(OnMissing[Logger]()))
+//	// application
+//	Set[Logger](appLogger)
+//	...
+//	err := Resolve() // registers defaultLogger only if nothing else did
+//
+// Valid:
+//
+//	Set(val, WithCondition(OnEnv("FEATURE_X")))
+//
+//	SetAs(val, types, WithCondition(OnPresent[*sql.DB]()))
+//
+//	Provide(newCache, WithCondition(OnMissing[Cache]())) // newCache is called only if the cache is registered
+//
+// Invalid:
+//
+//	NewRegistry(WithCondition(c)) // returns ErrNotSupported
+//
+//	Set(val, WithCondition(nil))  // returns ErrBadOption
+//
+//	Get[T](WithCondition(c))      // returns ErrNotSupported, same for all other ops
+func (t *optionsBuilder) WithCondition(conditions ...Condition) *optionsBuilder {
+	return t.and(withConditionOption(conditions...))
+}
+
+// WithPrimary marks the instance as the primary one of its type, unnamed lookups ([Get] without [WithName]) return it
+// instead of the instance registered under the default name. Only one instance per type (and namespace) can be primary,
+// lookups only consider the primary instance of the namespace they target, not the ones of nested namespaces.
+//
+// Valid:
+//
+//	Set(pg, WithName("pg").WithPrimary())
+//	Get[*sql.DB]() // returns pg
+//
+//	Swap(val, WithName("pg").WithPrimary()) // same for the other ops registering instances
+//
+// Invalid:
+//
+//	Set(mysql, WithName("mysql").WithPrimary()) // returns ErrNotUniqueType if another instance is primary
+//
+//	NewRegistry(WithPrimary()) // returns ErrNotSupported
+//
+//	Get[T](WithPrimary())      // returns ErrNotSupported, same for GetAll, GetMatching, Unset and WithProfile
+func (t *optionsBuilder) WithPrimary() *optionsBuilder {
+	return t.and(withPrimaryOption())
+}
+
+// WithPriority sets the instance priority (0 by default), higher first. [GetAllOf] lists instances in priority order and
+// unnamed lookups return the highest priority instance if there is neither a primary (see [WithPrimary]) nor a default instance.
+// Replacing an instance (ex. using [Swap]) without WithPrimary or WithPriority keeps its priority.
+//
+// Valid:
+//
+//	Set(fast, WithName("fast").WithPriority(10))
+//	Set(slow, WithName("slow").WithPriority(1))
+//	Get[Cache]()      // returns fast
+//	GetAllOf[Cache]() // returns [fast slow]
+//
+// Invalid:
+//
+//	Get[T]() // returns ErrNotUniqueType if multiple instances share the highest priority
+//
+//	Set(val, WithPriority(1), WithPriority(2)) // returns ErrBadOption
+//
+//	NewRegistry(WithPriority(1)) // returns ErrNotSupported, same for Get, GetAll, GetMatching, Unset and WithProfile
+func (t *optionsBuilder) WithPriority(n int) *optionsBuilder {
+	return t.and(withPriorityOption(n))
+}
+
+// WithCheckTimeout sets the time each health check gets (DefaultCheckTimeout by default), it only applies to [Health] and [HealthHandler]
+//
+// Valid:
+//
+//	Health(ctx, WithCheckTimeout(time.Second))
+//
+//	HealthHandler(WithNamespace("db").WithCheckTimeout(500 * time.Millisecond))
+//
+// Invalid:
+//
+//	NewRegistry(WithCheckTimeout(time.Second)) // returns ErrNotSupported
+//
+//	Set(val, WithCheckTimeout(time.Second))    // returns ErrNotSupported, same for every op except Health
+//
+//	Health(ctx, WithCheckTimeout(0))           // returns ErrBadOption in the report
+func (t *optionsBuilder) WithCheckTimeout(d time.Duration) *optionsBuilder {
+	return t.and(withCheckTimeoutOption(d))
+}
+
+// WithAlias registers the instance under additional names, so one instance can answer to multiple names (ex. "primary" and "default").
+//
+// Aliases are linked to the canonical instance (the one registered using [WithName] or the default name), unsetting it also unsets all its aliases.
+// Unsetting an alias only removes that alias.
+//
+// Valid:
+//
+//	Set(val, WithName("primary").WithAlias("default")) // val is retrievable using both names
+//
+//	SetAs(val, types, WithAlias("default")) // aliases apply to every type
+//
+// Invalid:
+//
+//	NewRegistry(WithAlias("x")) // returns ErrNotSupported
+//
+//	Get[T](WithAlias("x")) // returns ErrNotSupported, same for GetAll and Unset
+func (t *optionsBuilder) WithAlias(names ...string) *optionsBuilder {
+	return t.and(withAliasOption(names...))
+}
+
+// WithNamespace treats instance names as slash separated paths and scopes the op to ns, see [Namespace] for a reusable variant.
+//
+// Valid:
+//
+//	NewRegistry(WithNamespace("payments")) // every instance name in the registry is prefixed by "payments/" (including the default name)
+//
+//	Set(val, WithNamespace("payments").WithName("db")) // registers the instance as "payments/db"
+//
+//	Get[T](WithNamespace("payments").WithName("db")) // returns the instance named "payments/db"
+//
+//	GetAll(WithNamespace("payments")) // returns only the instances inside "payments", names are relative to it
+//
+//	Unset[T](WithNamespace("payments").WithName("db")) // unsets the instance named "payments/db"
+//
+// Call level namespaces are nested inside the registry namespace, multiple call level namespaces are nested in order of appearance.
+func (t *optionsBuilder) WithNamespace(ns string) *optionsBuilder {
+	return t.and(withNamespaceOption(ns))
+}
+
+// WithNamePattern filters instances by name using a [Glob] or [Regexp] pattern
+//
+// Valid:
+//
+//	NewRegistry(WithCloneEntries(src).WithNamePattern(Glob("db/*"))) // clones only the entries whose names match
+//
+//	GetAll(WithNamePattern(Regexp(`^db/replica-\d+$`))) // returns only the instances whose names match
+//
+// Invalid:
+//
+//	Get[T](WithNamePattern(p)) // returns ErrNotSupported, use GetMatching
+//
+//	Set(val, WithNamePattern(p)) // returns ErrNotSupported
+//
+//	Unset[T](WithNamePattern(p)) // returns ErrNotSupported
+func (t *optionsBuilder) WithNamePattern(p NamePattern) *optionsBuilder {
+	return t.and(withNamePatternOption(p))
+}
+
 // WithAccessibility enforce minimum accessibility level of types.
 //
 // Best used at registry level, it will then require all types to be **at least** as visible as the setting.
@@ -98,6 +372,8 @@ func (t *optionsBuilder) WithName(n string) *optionsBuilder {
 //
 //	NewRegistry(WithAccessibility(access.AccessibleEverywhere)) // makes sure that all registered instances are accessible everywhere
 //
+//	NewRegistry(WithAccessibility(access.AccessibleInsideInternalTree)) // also allows exported types of internal packages the caller can import
+//
 //	Set(val, WithAccessibility(access.AccessibleInsidePackage)) // make sure that the instance being set is accessible at least in the package where it is registered
 //
 //	GetAll(WithAccessibility(access.AccessibleInsidePackage)) // returns all instances with the given accessibility. Types are checked for acessibility in the callers package
@@ -125,6 +401,8 @@ func (t *optionsBuilder) WithAccessibility(a access.Accessibility) *optionsBuild
 //
 //	Set(val, WithNamedness(access.NamedType)) // fails if val has an anonymous type
 //
+//	NewRegistry(WithNamedness(access.DefinedType)) // also rejects predeclared types, nobody can register a bare string or int
+//
 //	GetAll(WithNamedness(access.AnonymousType)) // returns all instances with anonymous types
 //
 // # Invalid:
@@ -136,6 +414,61 @@ func (t *optionsBuilder) WithNamedness(n access.Namedness) *optionsBuilder {
 	return t.and(withNamednessOption(n))
 }
 
+// WithMetrics reports measurements of all registry ops to m (op counters, Get hits/misses, constraint violations and lock wait time).
+//
+// See [ExpvarMetrics] for an implementation based on the expvar package.
+//
+// Valid:
+//
+//	NewRegistry(WithMetrics(NewExpvarMetrics("registry"))) // every op on the registry is measured
+//
+// Invalid:
+//
+//	Get[T](WithMetrics(m)) // returns ErrNotSupported
+//
+//	Set(val, WithMetrics(m)) // returns ErrNotSupported
+//
+//	GetAll(WithMetrics(m)) // returns ErrNotSupported
+//
+//	Unset[T](WithMetrics(m)) // returns ErrNotSupported
+func (t *optionsBuilder) WithMetrics(m Metrics) *optionsBuilder {
+	return t.and(withMetricsOption(m))
+}
+
+// WithLogger logs every mutation (Set, Unset) and failed lookup on the registry with structured attributes (op, type, name, caller, error).
+//
+// Levels default to Info for mutations and Debug for failed lookups, change them using [WithLogLevel].
+//
+// Valid:
+//
+//	NewRegistry(WithLogger(slog.Default())) // every mutation and failed lookup is logged
+//
+// Invalid:
+//
+//	Get[T](WithLogger(l)) // returns ErrNotSupported
+//
+//	Set(val, WithLogger(l)) // returns ErrNotSupported
+//
+//	GetAll(WithLogger(l)) // returns ErrNotSupported
+//
+//	Unset[T](WithLogger(l)) // returns ErrNotSupported
+func (t *optionsBuilder) WithLogger(l *slog.Logger) *optionsBuilder {
+	return t.and(withLoggerOption(l))
+}
+
+// WithLogLevel sets the level used for logging op, see [WithLogger]. Pass it once per op.
+//
+// Valid:
+//
+//	NewRegistry(WithLogger(l).WithLogLevel(OpSet, slog.LevelDebug)) // log Set at debug level
+//
+// Invalid:
+//
+//	Set(val, WithLogLevel(OpSet, slog.LevelDebug)) // returns ErrNotSupported, same for all other ops
+func (t *optionsBuilder) WithLogLevel(op Op, level slog.Level) *optionsBuilder {
+	return t.and(withLogLevelOption(op, level))
+}
+
 // WithCloneConfig copies configuration from the provided registry
 //
 // Valid:
@@ -153,6 +486,9 @@ func (t *optionsBuilder) WithNamedness(n access.Namedness) *optionsBuilder {
 //
 //	Unset[T](WithCloneConfig(src)) // returns ErrNotSupported
 //
+// Settings passed to the same NewRegistry (ex. WithPolicy or WithLogger) are kept over the copied ones, validators and policies of both apply.
+// The default name is always copied.
+//
 // This option is applied 3rd to last, just before [WithCloneRegistry] and [WithCloneEntries]
 func (t *optionsBuilder) WithCloneConfig(src *registry) *optionsBuilder {
 	return t.and(withCloneConfigOption(src))
@@ -196,6 +532,8 @@ func (t *optionsBuilder) WithCloneEntries(src *registry) *optionsBuilder {
 //
 //	Unset[T](WithCloneConfig(src)) // returns ErrNotSupported
 //
+// The config is copied like [WithCloneConfig] does.
+//
 // This option always applies last to check if other incompatible options have been called before it
 func (t *optionsBuilder) WithCloneRegistry(src *registry) *optionsBuilder {
 	return t.and(withCloneRegistryOption(src))
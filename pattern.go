@@ -0,0 +1,127 @@
+package reg
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+)
+
+// NamePattern matches instance names, create it using [Glob] or [Regexp].
+//
+// Invalid patterns are reported with ErrBadOption by the op they are passed to.
+type NamePattern struct {
+	expr string
+	re   *regexp.Regexp
+	err  error
+}
+
+// Glob creates a pattern with [path.Match] syntax. As in paths '*' does not match '/', so
+//
+//	Glob("db/*") // matches "db/primary" and "db/replica-1" but not "db" or "db/replica/1"
+func Glob(pattern string) NamePattern {
+	_, err := path.Match(pattern, "")
+
+	return NamePattern{expr: pattern, err: err}
+}
+
+// Regexp creates a pattern with [regexp] syntax, it is not anchored unless expr is
+//
+//	Regexp(`^db/replica-\d+$`) // matches "db/replica-1" and "db/replica-2" but not "db/primary"
+func Regexp(expr string) NamePattern {
+	re, err := regexp.Compile(expr)
+
+	return NamePattern{expr: expr, re: re, err: err}
+}
+
+// Match reports whether name matches the pattern, invalid patterns match nothing
+func (t NamePattern) Match(name string) bool {
+	if t.err != nil {
+		return false
+	}
+
+	if t.re != nil {
+		return t.re.MatchString(name)
+	}
+
+	ok, _ := path.Match(t.expr, name)
+
+	return ok
+}
+
+func (t NamePattern) String() string {
+	return t.expr
+}
+
+// validate returns an error if the pattern can't be used
+func (t NamePattern) validate() error {
+	if t.err != nil {
+		return fmt.Errorf("name pattern '%s': %w: %w", t.expr, ErrBadOption, t.err)
+	}
+
+	if t.expr == "" && t.re == nil {
+		return fmt.Errorf("empty name pattern: %w", ErrBadOption)
+	}
+
+	return nil
+}
+
+// GetMatching retrieves all instances of T whose name matches the pattern, keyed by name (relative to the namespace, see [WithNamespace]).
+// If no instance matches the returned map is empty.
+//
+//	replicas, err := GetMatching[*sql.DB](Glob("db/replica-*"))
+//
+// Supports the same options as [Get] except [WithName]. A pattern passed using [WithNamePattern] narrows the result further, names must match both.
+func GetMatching[T any](pattern NamePattern, opts ...Option) (map[string]T, error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpGetMatching, rt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.cleanup()
+
+	out, err := getMatching[T](r, pattern)
+	r.observe(OpGetMatching, rt, err)
+
+	return out, err
+}
+
+// getMatching from the registry, caller must handle mutex locking
+func getMatching[T any](r *registry, pattern NamePattern) (map[string]T, error) {
+	if err := pattern.validate(); err != nil {
+		return nil, err
+	}
+
+	co := r.callOptions
+	if co.uniqueName {
+		return nil, fmt.Errorf("GetMatching WithUniqueName: %w, use WithUniqueType instead", ErrNotSupported)
+	}
+
+	if co.name != "" || co.aliases != nil {
+		return nil, fmt.Errorf("GetMatching WithName or WithAlias: %w, use the pattern instead", ErrNotSupported)
+	}
+
+	if co.profile != "" || co.conditions != nil || co.nonNil || co.primary || co.prioritized {
+		return nil, fmt.Errorf("GetMatching WithProfile, WithCondition, WithNonNil, WithPrimary or WithPriority: %w", ErrNotSupported)
+	}
+
+	rt := reflect.TypeFor[T]()
+	instances := r.store[rt]
+
+	if (r.config.uniqueTypes || co.uniqueType) && r.scopedLen(rt) > 1 {
+		return nil, fmt.Errorf("GetMatching '%s' failed: %w", rt, ErrNotUniqueType)
+	}
+
+	ns := r.namespace()
+
+	out := map[string]T{}
+	for name, instance := range instances {
+		if name, ok := relName(ns, name); ok && pattern.Match(name) && (co.namePattern == nil || co.namePattern.Match(name)) {
+			out[name], _ = instance.(T)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,144 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNamePattern_Match(t *testing.T) {
+	cases := []struct {
+		pattern NamePattern
+		name    string
+		want    bool
+	}{
+		{Glob("db/*"), "db/primary", true},
+		{Glob("db/*"), "db", false},
+		{Glob("db/*"), "db/replica/1", false},
+		{Glob("db/replica-?"), "db/replica-1", true},
+		{Regexp(`^db/replica-\d+$`), "db/replica-12", true},
+		{Regexp(`^db/replica-\d+$`), "db/primary", false},
+		{Regexp(`primary`), "db/primary", true},
+		{Glob("[a-"), "a", false},
+	}
+
+	for _, tc := range cases {
+		if got := tc.pattern.Match(tc.name); got != tc.want {
+			t.Fatalf("%q.Match(%q) = %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+}
+
+func newPatternTestReg(t *testing.T) *registry {
+	t.Helper()
+
+	r := newTestReg(t)
+	for i, name := range []string{"db/primary", "db/replica-1", "db/replica-2", "cache"} {
+		MustSet(ExportedNamedTester{ID: i}, WithRegistry(r).WithName(name))
+	}
+
+	return r
+}
+
+func TestGetMatching(t *testing.T) {
+	r := newPatternTestReg(t)
+
+	got, err := GetMatching[ExportedNamedTester](Glob("db/replica-*"), WithRegistry(r))
+	if err != nil {
+		t.Fatalf("GetMatching glob error = %v", err)
+	}
+
+	want := map[string]ExportedNamedTester{"db/replica-1": {ID: 1}, "db/replica-2": {ID: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetMatching glob = %v, want %v", got, want)
+	}
+
+	got, err = GetMatching[ExportedNamedTester](Regexp(`^(db/primary|cache)$`), WithRegistry(r))
+	if err != nil {
+		t.Fatalf("GetMatching regexp error = %v", err)
+	}
+
+	if len(got) != 2 || got["cache"].ID != 3 || got["db/primary"].ID != 0 {
+		t.Fatalf("GetMatching regexp = %v", got)
+	}
+
+	got, err = GetMatching[ExportedNamedTester](Glob("nothing*"), WithRegistry(r))
+	if err != nil || len(got) != 0 {
+		t.Fatalf("GetMatching without matches = %v, %v, want empty map", got, err)
+	}
+
+	// WithNamePattern narrows the result
+	got, err = GetMatching[ExportedNamedTester](Glob("db/*"), WithRegistry(r), WithNamePattern(Glob("*/*-2")))
+	if err != nil || len(got) != 1 || got["db/replica-2"].ID != 2 {
+		t.Fatalf("GetMatching WithNamePattern = %v, %v, want only db/replica-2", got, err)
+	}
+}
+
+func TestGetMatching_Errors(t *testing.T) {
+	r := newPatternTestReg(t)
+
+	if _, err := GetMatching[ExportedNamedTester](Regexp("("), WithRegistry(r)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("GetMatching invalid regexp err = %v, want ErrBadOption", err)
+	}
+
+	if _, err := GetMatching[ExportedNamedTester](NamePattern{}, WithRegistry(r)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("GetMatching empty pattern err = %v, want ErrBadOption", err)
+	}
+
+	if _, err := GetMatching[ExportedNamedTester](Glob("*"), WithRegistry(r).WithName("x")); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetMatching WithName err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetMatching[ExportedNamedTester](Glob("db/*"), WithRegistry(r).WithUniqueType()); !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("GetMatching WithUniqueType err = %v, want ErrNotUniqueType", err)
+	}
+}
+
+func TestWithNamePattern(t *testing.T) {
+	src := newPatternTestReg(t)
+	MustSet(7, WithRegistry(src).WithName("db/primary"))
+	MustSet(8, WithRegistry(src).WithName("other"))
+
+	all, err := GetAll(WithRegistry(src).WithNamePattern(Glob("db/*")))
+	if err != nil {
+		t.Fatalf("GetAll WithNamePattern error = %v", err)
+	}
+
+	if got := len(all[reflect.TypeFor[ExportedNamedTester]()]); got != 3 {
+		t.Fatalf("GetAll WithNamePattern tester count = %d, want 3", got)
+	}
+
+	if got := len(all[reflect.TypeFor[int]()]); got != 1 {
+		t.Fatalf("GetAll WithNamePattern int count = %d, want 1", got)
+	}
+
+	dest, err := NewRegistry(WithCloneEntries(src).WithNamePattern(Regexp("primary|cache")))
+	if err != nil {
+		t.Fatalf("NewRegistry WithCloneEntries WithNamePattern error = %v", err)
+	}
+
+	all = MustGetAll(WithRegistry(dest))
+	if got := len(all[reflect.TypeFor[ExportedNamedTester]()]); got != 2 {
+		t.Fatalf("cloned tester count = %d, want 2", got)
+	}
+
+	if _, ok := all[reflect.TypeFor[int]()]["other"]; ok {
+		t.Fatalf("entry not matching the pattern was cloned")
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(src).WithNamePattern(Glob("*"))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Get WithNamePattern err = %v, want ErrNotSupported", err)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(src).WithNamePattern(Glob("*"))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithNamePattern err = %v, want ErrNotSupported", err)
+	}
+
+	if err := Unset(ExportedNamedTester{}, WithRegistry(src).WithNamePattern(Glob("*"))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Unset WithNamePattern err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetAll(WithRegistry(src).WithNamePattern(Glob("*")).WithNamePattern(Glob("*"))); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("multiple WithNamePattern err = %v, want ErrBadOption", err)
+	}
+}
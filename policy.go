@@ -0,0 +1,101 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Policy is a type level rule, configured using [WithPolicy] and checked for every type an instance is registered under.
+//
+// Check returns a descriptive error if rt violates the policy, the registry wraps it in ErrPolicyViolation.
+type Policy interface {
+	Check(rt reflect.Type) error
+}
+
+// PolicyFunc is a function implementing [Policy]
+type PolicyFunc func(rt reflect.Type) error
+
+func (t PolicyFunc) Check(rt reflect.Type) error {
+	return t(rt)
+}
+
+// RequireInterfaceKeys requires instances to be registered under interface types, keeping consumers decoupled from implementations
+func RequireInterfaceKeys() Policy {
+	return PolicyFunc(func(rt reflect.Type) error {
+		if rt.Kind() != reflect.Interface {
+			return fmt.Errorf("wanted an interface type but got '%s'", rt.Kind())
+		}
+
+		return nil
+	})
+}
+
+// ForbidStructValues forbids non pointer struct types, their instances are copied on every Get so changes are never shared
+func ForbidStructValues() Policy {
+	return PolicyFunc(func(rt reflect.Type) error {
+		if rt.Kind() == reflect.Struct {
+			return fmt.Errorf("struct values are copied on Get, register '*%s' instead", rt)
+		}
+
+		return nil
+	})
+}
+
+// ForbidTypes forbids registering instances under any of the types
+func ForbidTypes(types ...reflect.Type) Policy {
+	return PolicyFunc(func(rt reflect.Type) error {
+		if slices.Contains(types, rt) {
+			return fmt.Errorf("type '%s' is forbidden", rt)
+		}
+
+		return nil
+	})
+}
+
+// ForbidAnyAndError forbids the too broad any and error types, every value (or error) would match them
+func ForbidAnyAndError() Policy {
+	return ForbidTypes(reflect.TypeFor[any](), reflect.TypeFor[error]())
+}
+
+// AllowPackages only allows types defined in one of the packages (pointers are dereferenced first).
+// A package ending in "/..." also allows all the packages below it, same as the go tool:
+//
+//	AllowPackages("github.com/acme/api", "github.com/acme/services/...")
+//
+// Predeclared and anonymous types are not defined in any package so they are never allowed.
+func AllowPackages(pkgs ...string) Policy {
+	return PolicyFunc(func(rt reflect.Type) error {
+		named := rt
+		for named.Kind() == reflect.Pointer && named.Name() == "" {
+			named = named.Elem()
+		}
+
+		pkg := named.PkgPath()
+		if named.Name() != "" && pkg != "" {
+			for _, allowed := range pkgs {
+				if pkg == allowed {
+					return nil
+				}
+
+				if tree, ok := strings.CutSuffix(allowed, "/..."); ok && (pkg == tree || strings.HasPrefix(pkg, tree+"/")) {
+					return nil
+				}
+			}
+		}
+
+		return fmt.Errorf("type '%s' is not defined in an allowed package %q", rt, pkgs)
+	})
+}
+
+// checkPolicies checks rt against all the policies of the registry
+func checkPolicies(r *registry, rt reflect.Type) error {
+	for _, policy := range r.config.policies {
+		if err := policy.Check(rt); err != nil {
+			return fmt.Errorf("Set '%s' failed: %w. %w", rt, ErrPolicyViolation, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package reg
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type policyGreeter interface{ Greet() string }
+
+type policyImpl struct{}
+
+func (policyImpl) Greet() string { return "hi" }
+
+func TestPolicy_RequireInterfaceKeys(t *testing.T) {
+	r := newTestReg(t, WithPolicy(RequireInterfaceKeys()))
+
+	err := Set(ExportedNamedTester{}, WithRegistry(r))
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set struct err = %v, want ErrPolicyViolation", err)
+	}
+
+	if !strings.Contains(err.Error(), "interface") {
+		t.Fatalf("error %q does not describe the violated policy", err)
+	}
+
+	if err := Set[policyGreeter](policyImpl{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set interface error = %v", err)
+	}
+
+	// policies are checked for every type of SetAs
+	err = SetAs(policyImpl{}, []reflect.Type{reflect.TypeFor[policyGreeter]()}, WithRegistry(r), WithName("other"))
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("SetAs struct err = %v, want ErrPolicyViolation", err)
+	}
+}
+
+func TestPolicy_ForbidStructValues(t *testing.T) {
+	r := newTestReg(t, WithPolicy(ForbidStructValues()))
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set struct value err = %v, want ErrPolicyViolation", err)
+	}
+
+	if err := Set(&ExportedNamedTester{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set struct pointer error = %v", err)
+	}
+}
+
+func TestPolicy_ForbidAnyAndError(t *testing.T) {
+	r := newTestReg(t, WithPolicy(ForbidAnyAndError()))
+
+	if err := Set[any](1, WithRegistry(r)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set any err = %v, want ErrPolicyViolation", err)
+	}
+
+	if err := Set[error](errors.New("x"), WithRegistry(r)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("Set error err = %v, want ErrPolicyViolation", err)
+	}
+
+	if err := Set(1, WithRegistry(r)); err != nil {
+		t.Fatalf("Set int error = %v", err)
+	}
+}
+
+func TestPolicy_AllowPackages(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkgs    []string
+		rt      reflect.Type
+		allowed bool
+	}{
+		{"exact", []string{thisPackage}, reflect.TypeFor[ExportedNamedTester](), true},
+		{"pointer", []string{thisPackage}, reflect.TypeFor[*ExportedNamedTester](), true},
+		{"tree", []string{"github.com/mp3cko/..."}, reflect.TypeFor[ExportedNamedTester](), true},
+		{"other", []string{"github.com/acme"}, reflect.TypeFor[ExportedNamedTester](), false},
+		{"prefix is not a tree", []string{"github.com/mp3cko/reg..."}, reflect.TypeFor[ExportedNamedTester](), false},
+		{"predeclared", []string{thisPackage}, reflect.TypeFor[int](), false},
+		{"anonymous", []string{thisPackage}, reflect.TypeFor[[]ExportedNamedTester](), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AllowPackages(tt.pkgs...).Check(tt.rt)
+			if (err == nil) != tt.allowed {
+				t.Fatalf("AllowPackages(%q).Check(%s) = %v, allowed %v", tt.pkgs, tt.rt, err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestPolicy_Options(t *testing.T) {
+	if _, err := NewRegistry(WithPolicy(nil)); !errors.Is(err, ErrBadOption) {
+		t.Fatalf("WithPolicy(nil) err = %v, want ErrBadOption", err)
+	}
+
+	r := newTestReg(t)
+	if err := Set(1, WithRegistry(r), WithPolicy(ForbidStructValues())); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("WithPolicy on Set err = %v, want ErrNotSupported", err)
+	}
+
+	// policies are copied with the config
+	src := newTestReg(t, WithPolicy(ForbidAnyAndError()))
+	clone := newTestReg(t, WithCloneConfig(src))
+	if err := Set[any](1, WithRegistry(clone)); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("cloned config Set any err = %v, want ErrPolicyViolation", err)
+	}
+}
@@ -0,0 +1,201 @@
+package reg
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// GetAllOf retrieves all instances of T in priority order: the primary instance of the targeted namespace first (see [WithPrimary]),
+// then by descending priority (see [WithPriority]) and name. Aliases and additional types of other instances are skipped.
+//
+//	handlers, err := GetAllOf[Handler](WithNamespace("http"))
+//
+// Supports the same options as [GetMatching], pass the pattern using [WithNamePattern].
+func GetAllOf[T any](opts ...Option) ([]T, error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpGetAll, rt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.cleanup()
+
+	out, err := getAllOf[T](r)
+	r.observe(OpGetAll, rt, err)
+
+	return out, err
+}
+
+// getAllOf from the registry, caller must handle mutex locking
+func getAllOf[T any](r *registry) ([]T, error) {
+	co := r.callOptions
+	if co.uniqueName {
+		return nil, fmt.Errorf("GetAllOf WithUniqueName: %w, use WithUniqueType instead", ErrNotSupported)
+	}
+
+	if co.name != "" || co.aliases != nil {
+		return nil, fmt.Errorf("GetAllOf WithName or WithAlias: %w, use WithNamePattern instead", ErrNotSupported)
+	}
+
+	if co.profile != "" || co.conditions != nil || co.nonNil || co.primary || co.prioritized {
+		return nil, fmt.Errorf("GetAllOf WithProfile, WithCondition, WithNonNil, WithPrimary or WithPriority: %w", ErrNotSupported)
+	}
+
+	rt := reflect.TypeFor[T]()
+
+	if (r.config.uniqueTypes || co.uniqueType) && r.scopedLen(rt) > 1 {
+		return nil, fmt.Errorf("GetAllOf '%s' failed: %w", rt, ErrNotUniqueType)
+	}
+
+	keys := r.ranked(rt)
+
+	out := make([]T, 0, len(keys))
+	for _, key := range keys {
+		if name, _ := relName(r.namespace(), key.name); co.namePattern == nil || co.namePattern.Match(name) {
+			val, _ := r.store[rt][key.name].(T)
+			out = append(out, val)
+		}
+	}
+
+	return out, nil
+}
+
+// ranked returns the keys of the instances of rt inside the namespace targeted by the current call in priority order, aliases of other instances are skipped.
+// Primary instances of nested namespaces are ranked like the others, see preferred
+func (t *registry) ranked(rt reflect.Type) []entryKey {
+	ns := t.namespace()
+
+	var keys []entryKey
+	for name := range t.store[rt] {
+		if _, ok := relName(ns, name); !ok {
+			continue
+		}
+
+		key := entryKey{rt, name}
+		if m := t.meta[key]; m != nil && m.canonical != nil && m.canonical.rt == rt {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b entryKey) int {
+		ma, mb := valueOrDefault(t.meta[a], new(entryMeta)), valueOrDefault(t.meta[b], new(entryMeta))
+
+		return cmp.Or(
+			compareBool(mb.primary && parentName(b.name) == ns, ma.primary && parentName(a.name) == ns),
+			cmp.Compare(mb.priority, ma.priority),
+			cmp.Compare(a.name, b.name),
+		)
+	})
+
+	return keys
+}
+
+// preferred returns the key an unnamed Get of rt resolves to: the primary instance, else the default one (if it resolves for the active profiles),
+// else the one with the highest priority if any instance was registered using WithPriority.
+//
+// Only the instances directly inside the targeted namespace are candidates, so the primary instance of a nested namespace
+// never overrides the default instance of an outer one. ambiguous is set if multiple instances share the highest priority.
+// Caller must hold the lock and the call options
+func (t *registry) preferred(rt reflect.Type) (key entryKey, ambiguous bool) {
+	def := entryKey{rt, t.entryName()}
+	ns := t.namespace()
+
+	var (
+		best     *entryKey
+		primary  *entryKey
+		priority int
+	)
+
+	for k, m := range t.meta {
+		if k.rt != rt || m.canonical != nil && m.canonical.rt == rt {
+			continue
+		}
+
+		if parentName(k.name) != ns {
+			continue
+		}
+
+		if m.primary {
+			primary = &k
+			continue
+		}
+
+		if !m.prioritized {
+			continue
+		}
+
+		switch {
+		case best == nil || m.priority > priority:
+			best, priority, ambiguous = &k, m.priority, false
+		case m.priority == priority:
+			ambiguous = true
+		}
+	}
+
+	if primary != nil {
+		return *primary, false
+	}
+
+	// the default instance is skipped if it is only registered for inactive profiles
+	if _, ok, conflicts := t.resolve(def); ok || conflicts != nil || best == nil {
+		return def, false
+	}
+
+	return *best, ambiguous
+}
+
+// checkPrimary returns an error if another instance of rt in the same namespace as name is primary, caller must hold the lock.
+// Nested namespaces can each have a primary instance, see preferred
+func (t *registry) checkPrimary(rt reflect.Type, name string) error {
+	ns := parentName(name)
+
+	for k, m := range t.meta {
+		if k.rt != rt || !m.primary || k.name == name {
+			continue
+		}
+
+		if parentName(k.name) == ns {
+			return fmt.Errorf("Set '%s' WithPrimary failed, '%s' is primary: %w", rt, k.name, ErrNotUniqueType)
+		}
+	}
+
+	return nil
+}
+
+// rank records the primary and priority options of the current call on key, replace keeps the previous ones if the call sets none. Caller must hold the lock
+func (t *registry) rank(key entryKey, primary, replace bool) {
+	co := t.callOptions
+
+	if replace && !co.primary && !co.prioritized {
+		return
+	}
+
+	if primary || co.prioritized {
+		m := t.metaFor(key)
+		m.primary, m.priority, m.prioritized = primary, co.priority, co.prioritized
+
+		return
+	}
+
+	if m := t.meta[key]; m != nil {
+		m.primary, m.priority, m.prioritized = false, 0, false
+		t.pruneMeta(key)
+	}
+}
+
+// compareBool orders false before true
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,222 @@
+package reg
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPriority_Primary(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithName("a"))
+	if err := Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("b"), WithPrimary()); err != nil {
+		t.Fatalf("Set primary error = %v", err)
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 2 {
+		t.Fatalf("Get() = %v, %v, want the primary", got, err)
+	}
+
+	// the primary wins over the default instance
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r))
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 2 {
+		t.Fatalf("Get() = %v, want the primary", got)
+	}
+
+	if err := Set(ExportedNamedTester{ID: 4}, WithRegistry(r), WithName("c"), WithPrimary()); !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("Set second primary err = %v, want ErrNotUniqueType", err)
+	}
+
+	// replacing keeps the primary
+	if _, _, err := Swap(ExportedNamedTester{ID: 5}, WithRegistry(r), WithName("b")); err != nil {
+		t.Fatalf("Swap() error = %v", err)
+	}
+
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 5 {
+		t.Fatalf("Get() after Swap = %v, want the replaced primary", got)
+	}
+
+	// Set registers anew
+	_ = Set(ExportedNamedTester{ID: 6}, WithRegistry(r), WithName("b"))
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 3 {
+		t.Fatalf("Get() after Set = %v, want the default instance", got)
+	}
+
+	_ = Set(ExportedNamedTester{ID: 7}, WithRegistry(r), WithName("c"), WithPrimary())
+	_ = Unset(ExportedNamedTester{}, WithRegistry(r), WithName("c"))
+	if got, _ := Get[ExportedNamedTester](WithRegistry(r)); got.ID != 3 {
+		t.Fatalf("Get() after Unset = %v, want the default instance", got)
+	}
+}
+
+func TestPriority_Highest(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithName("low"), WithPriority(1))
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("none"))
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithName("high"), WithPriority(10))
+	_ = Set(ExportedNamedTester{ID: 4}, WithRegistry(r), WithName("negative"), WithPriority(-1))
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 3 {
+		t.Fatalf("Get() = %v, %v, want the highest priority", got, err)
+	}
+
+	all, err := GetAllOf[ExportedNamedTester](WithRegistry(r))
+	if err != nil {
+		t.Fatalf("GetAllOf() error = %v", err)
+	}
+
+	var ids []int
+	for _, v := range all {
+		ids = append(ids, v.ID)
+	}
+
+	if !slices.Equal(ids, []int{3, 1, 2, 4}) {
+		t.Fatalf("GetAllOf() = %v, want [3 1 2 4]", ids)
+	}
+
+	_ = Set(ExportedNamedTester{ID: 5}, WithRegistry(r), WithName("tie"), WithPriority(10))
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("Get() err = %v, want ErrNotUniqueType", err)
+	}
+
+	// named lookups are not affected
+	if got, err := Get[ExportedNamedTester](WithRegistry(r), WithName("low")); err != nil || got.ID != 1 {
+		t.Fatalf("Get named = %v, %v", got, err)
+	}
+
+	entries, _ := Entries(WithRegistry(r))
+	if entries[0].Name != "high" || entries[0].Priority != 10 {
+		t.Fatalf("Entries()[0] = %+v, want high with priority 10", entries[0])
+	}
+}
+
+func TestPriority_GetAllOf(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithName("db/a"), WithAlias("db/alias"))
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("db/b"), WithPrimary())
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithName("cache"))
+
+	all, err := GetAllOf[ExportedNamedTester](WithRegistry(r), WithNamespace("db"))
+	if err != nil || len(all) != 2 || all[0].ID != 2 || all[1].ID != 1 {
+		t.Fatalf("GetAllOf() = %v, %v, want [2 1]", all, err)
+	}
+
+	all, err = GetAllOf[ExportedNamedTester](WithRegistry(r), WithNamePattern(Glob("cache")))
+	if err != nil || len(all) != 1 || all[0].ID != 3 {
+		t.Fatalf("GetAllOf pattern = %v, %v, want [3]", all, err)
+	}
+
+	if all, err := GetAllOf[int](WithRegistry(r)); err != nil || len(all) != 0 {
+		t.Fatalf("GetAllOf[int]() = %v, %v, want empty", all, err)
+	}
+
+	// primary and priority survive cloning
+	clone := newTestReg(t, WithCloneRegistry(r))
+	if got, err := Get[ExportedNamedTester](WithRegistry(clone), WithNamespace("db")); err != nil || got.ID != 2 {
+		t.Fatalf("Get from clone = %v, %v, want the primary", got, err)
+	}
+}
+
+func TestPriority_NestedPrimaries(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithName("db/a/x"), WithPrimary())
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("db/b/x"), WithPrimary())
+	if err := Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithName("db/x"), WithPrimary()); err != nil {
+		t.Fatalf("Set primary of the outer namespace error = %v", err)
+	}
+
+	if err := Set(ExportedNamedTester{ID: 4}, WithRegistry(r), WithNamespace("db"), WithName("y"), WithPrimary()); !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("Set second primary of db err = %v, want ErrNotUniqueType", err)
+	}
+
+	// only the primary of the targeted namespace is considered, every time
+	for range 20 {
+		if got, err := Get[ExportedNamedTester](WithRegistry(r), WithNamespace("db")); err != nil || got.ID != 3 {
+			t.Fatalf("Get() = %v, %v, want the primary of db", got, err)
+		}
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r), WithNamespace("db/b")); err != nil || got.ID != 2 {
+		t.Fatalf("Get() = %v, %v, want the primary of db/b", got, err)
+	}
+
+	// primaries of nested namespaces are not candidates
+	_ = Unset(ExportedNamedTester{}, WithRegistry(r), WithName("db/x"))
+	if _, err := Get[ExportedNamedTester](WithRegistry(r), WithNamespace("db")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() err = %v, want ErrNotFound", err)
+	}
+
+	all, err := GetAllOf[ExportedNamedTester](WithRegistry(r))
+	if err != nil || len(all) != 2 || all[0].ID != 1 || all[1].ID != 2 {
+		t.Fatalf("GetAllOf() = %v, %v, want [1 2]", all, err)
+	}
+}
+
+func TestPriority_NestedPrimaryKeepsDefault(t *testing.T) {
+	r := newTestReg(t)
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	if err := Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithNamespace("payments"), WithName("stripe"), WithPrimary()); err != nil {
+		t.Fatalf("Set nested primary error = %v", err)
+	}
+
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithNamespace("payments"), WithName("fast"), WithPriority(10))
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 1 {
+		t.Fatalf("Get() = %v, %v, want the root default instance", got, err)
+	}
+
+	if got, err := Get[ExportedNamedTester](WithRegistry(r), WithNamespace("payments")); err != nil || got.ID != 2 {
+		t.Fatalf("Get() in payments = %v, %v, want the primary of payments", got, err)
+	}
+
+	// without the default nested instances are still not candidates
+	_ = Unset(ExportedNamedTester{}, WithRegistry(r))
+	if _, err := Get[ExportedNamedTester](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPriority_Profiles(t *testing.T) {
+	r := newTestReg(t, WithActiveProfiles("prod"))
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithProfile("dev"))
+	_ = Set(ExportedNamedTester{ID: 2}, WithRegistry(r), WithName("high"), WithPriority(10))
+
+	// the default instance of an inactive profile doesn't hide the highest priority
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 2 {
+		t.Fatalf("Get() = %v, %v, want the highest priority", got, err)
+	}
+
+	_ = Set(ExportedNamedTester{ID: 3}, WithRegistry(r), WithProfile("prod"))
+	if got, err := Get[ExportedNamedTester](WithRegistry(r)); err != nil || got.ID != 3 {
+		t.Fatalf("Get() = %v, %v, want the default instance of the active profile", got, err)
+	}
+}
+
+func TestPriority_Options(t *testing.T) {
+	r := newTestReg(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"NewRegistry WithPrimary", func() error { _, err := NewRegistry(WithPrimary()); return err }(), ErrNotSupported},
+		{"NewRegistry WithPriority", func() error { _, err := NewRegistry(WithPriority(1)); return err }(), ErrNotSupported},
+		{"WithPriority twice", Set(1, WithRegistry(r), WithPriority(1), WithPriority(2)), ErrBadOption},
+		{"Get", func() error { _, err := Get[int](WithRegistry(r), WithPrimary()); return err }(), ErrNotSupported},
+		{"GetAllOf WithName", func() error { _, err := GetAllOf[int](WithRegistry(r), WithName("x")); return err }(), ErrNotSupported},
+		{"WithProfile", Set(1, WithRegistry(r), WithProfile("dev"), WithPrimary()), ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Fatalf("%s err = %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+}
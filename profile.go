@@ -0,0 +1,123 @@
+package reg
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// setProfiled registers val under key for the profile, caller must handle mutex locking and check the type and value beforehand
+func setProfiled(r *registry, key entryKey, val any, profile string, typeMustBeUnique, nameMustBeUnique bool) error {
+	if typeMustBeUnique && r.profiledLen(key.rt, profile) != 0 {
+		return fmt.Errorf("Set '%s' for profile '%s' failed: %w", key.rt, profile, ErrNotUniqueType)
+	}
+
+	if _, ok := r.profiles[key][profile]; ok && nameMustBeUnique {
+		if key.name != "" {
+			return fmt.Errorf("Set '%s' named '%s' for profile '%s' failed: %w", key.rt, key.name, profile, ErrNotUniqueName)
+		}
+
+		return fmt.Errorf("Set '%s' for profile '%s' failed: %w", key.rt, profile, ErrNotUniqueName)
+	}
+
+	if r.profiles == nil {
+		r.profiles = map[entryKey]map[string]any{}
+	}
+
+	if r.profiles[key] == nil {
+		r.profiles[key] = map[string]any{}
+	}
+
+	r.profiles[key][profile] = val
+	r.version++
+
+	return nil
+}
+
+// unsetProfiled removes the instance registered under key for the profile, reporting whether there was one. Caller must handle mutex locking
+func (t *registry) unsetProfiled(key entryKey, profile string) bool {
+	if _, ok := t.profiles[key][profile]; !ok {
+		return false
+	}
+
+	delete(t.profiles[key], profile)
+	if len(t.profiles[key]) == 0 {
+		delete(t.profiles, key)
+	}
+
+	t.version++
+
+	return true
+}
+
+// resolve returns the instance Get resolves key to: the one registered for the profile passed using WithProfile,
+// else the one registered for an active profile, falling back to the profile-less instance.
+//
+// If multiple active profiles provide an instance nothing is returned, conflicts holds their names. Caller must hold the lock and the call options
+func (t *registry) resolve(key entryKey) (val any, ok bool, conflicts []string) {
+	if profile := t.callOptions.profile; profile != "" {
+		val, ok = t.profiles[key][profile]
+		return val, ok, nil
+	}
+
+	switch conflicts = t.activeProfiles(key); len(conflicts) {
+	case 0:
+		val, ok = t.store[key.rt][key.name]
+		return val, ok, nil
+	case 1:
+		val, ok = t.profiles[key][conflicts[0]]
+		return val, ok, nil
+	default:
+		return nil, false, conflicts
+	}
+}
+
+// activeProfiles returns the active profiles providing an instance for key, in order of activation. They shadow the profile-less instance. Caller must hold the lock
+func (t *registry) activeProfiles(key entryKey) []string {
+	var profiles []string
+	for _, profile := range t.config.profiles {
+		if _, found := t.profiles[key][profile]; found {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	return profiles
+}
+
+// profiledLen returns the number of instances of rt registered for the profile inside the namespace targeted by the current call
+func (t *registry) profiledLen(rt reflect.Type, profile string) int {
+	ns := t.namespace()
+
+	var n int
+	for key, instances := range t.profiles {
+		if _, ok := instances[profile]; !ok || key.rt != rt {
+			continue
+		}
+
+		if _, ok := relName(ns, key.name); ok {
+			n++
+		}
+	}
+
+	return n
+}
+
+// cloneProfiles returns a deep copy of the instances registered for profiles
+func (t *registry) cloneProfiles() map[entryKey]map[string]any {
+	if t.profiles == nil {
+		return nil
+	}
+
+	clone := make(map[entryKey]map[string]any, len(t.profiles))
+	for key, instances := range t.profiles {
+		clone[key] = maps.Clone(instances)
+	}
+
+	return clone
+}
+
+// profileNames returns the sorted names of the profiles key is registered for
+func (t *registry) profileNames(key entryKey) []string {
+	return slices.Sorted(maps.Keys(t.profiles[key]))
+}
@@ -0,0 +1,166 @@
+package reg
+
+import (
+	"errors"
+	"testing"
+)
+
+// testStore is implemented by the per profile test stores
+type testStore interface{ Kind() string }
+
+type memStore struct{}
+
+func (memStore) Kind() string { return "mem" }
+
+type pgStore struct{}
+
+func (pgStore) Kind() string { return "pg" }
+
+func TestProfiles_Resolve(t *testing.T) {
+	r := newTestReg(t, WithActiveProfiles("dev"))
+
+	if err := Set[testStore](pgStore{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := Set[testStore](memStore{}, WithRegistry(r), WithProfile("dev")); err != nil {
+		t.Fatalf("Set dev error = %v", err)
+	}
+
+	if err := Set[testStore](pgStore{}, WithRegistry(r), WithProfile("prod")); err != nil {
+		t.Fatalf("Set prod error = %v", err)
+	}
+
+	got, err := Get[testStore](WithRegistry(r))
+	if err != nil || got.Kind() != "mem" {
+		t.Fatalf("Get() = %v, %v, want the dev store", got, err)
+	}
+
+	got, err = Get[testStore](WithRegistry(r), WithProfile("prod"))
+	if err != nil || got.Kind() != "pg" {
+		t.Fatalf("Get WithProfile(prod) = %v, %v, want the prod store", got, err)
+	}
+
+	if err := Unset[testStore](nil, WithRegistry(r), WithProfile("dev")); err != nil {
+		t.Fatalf("Unset dev error = %v", err)
+	}
+
+	// falls back to the profile-less instance
+	if got, err := Get[testStore](WithRegistry(r)); err != nil || got.Kind() != "pg" {
+		t.Fatalf("Get() after Unset = %v, %v, want the profile-less store", got, err)
+	}
+
+	if err := Unset[testStore](nil, WithRegistry(r), WithProfile("dev")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Unset dev again err = %v, want ErrNotFound", err)
+	}
+
+	if _, err := Get[testStore](WithRegistry(r), WithProfile("dev")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get WithProfile(dev) err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProfiles_Conflict(t *testing.T) {
+	r := newTestReg(t, WithActiveProfiles("dev", "local"))
+
+	_ = Set[testStore](memStore{}, WithRegistry(r), WithProfile("dev"))
+	_ = Set[testStore](pgStore{}, WithRegistry(r), WithProfile("local"))
+
+	if _, err := Get[testStore](WithRegistry(r)); !errors.Is(err, ErrProfileConflict) {
+		t.Fatalf("Get() err = %v, want ErrProfileConflict", err)
+	}
+
+	// names are resolved independently
+	_ = Set[testStore](memStore{}, WithRegistry(r), WithProfile("dev"), WithName("cache"))
+	if _, err := Get[testStore](WithRegistry(r), WithName("cache")); err != nil {
+		t.Fatalf("Get named error = %v", err)
+	}
+}
+
+func TestProfiles_Clone(t *testing.T) {
+	app := newTestReg(t, WithActiveProfiles("prod"))
+
+	_ = Set[testStore](pgStore{}, WithRegistry(app), WithProfile("prod"))
+	_ = Set[testStore](memStore{}, WithRegistry(app), WithProfile("test"))
+
+	test := newTestReg(t, WithCloneRegistry(app), WithActiveProfiles("test"))
+	if got, err := Get[testStore](WithRegistry(test)); err != nil || got.Kind() != "mem" {
+		t.Fatalf("Get from test clone = %v, %v, want the test store", got, err)
+	}
+
+	// profiles are inherited if not set
+	clone := newTestReg(t, WithCloneRegistry(app))
+	if got, err := Get[testStore](WithRegistry(clone)); err != nil || got.Kind() != "pg" {
+		t.Fatalf("Get from clone = %v, %v, want the prod store", got, err)
+	}
+
+	if got, err := Get[testStore](WithRegistry(app)); err != nil || got.Kind() != "pg" {
+		t.Fatalf("Get from source = %v, %v, want the prod store", got, err)
+	}
+
+	entries, err := Entries(WithRegistry(clone))
+	if err != nil || len(entries) != 2 || entries[0].Profile != "prod" || entries[1].Profile != "test" {
+		t.Fatalf("Entries() = %v, %v", entries, err)
+	}
+}
+
+func TestProfiles_Constraints(t *testing.T) {
+	r := newTestReg(t, WithUniqueName())
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r), WithProfile("dev")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r), WithProfile("dev")); !errors.Is(err, ErrNotUniqueName) {
+		t.Fatalf("Set twice err = %v, want ErrNotUniqueName", err)
+	}
+
+	// other profiles and the profile-less instance don't collide
+	if err := Set(ExportedNamedTester{}, WithRegistry(r), WithProfile("prod")); err != nil {
+		t.Fatalf("Set prod error = %v", err)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set profile-less error = %v", err)
+	}
+
+}
+
+func TestProfiles_UnsetNamespace(t *testing.T) {
+	r := newTestReg(t, WithActiveProfiles("dev"))
+
+	_ = Set(ExportedNamedTester{ID: 1}, WithRegistry(r), WithNamespace("payments"), WithProfile("dev"))
+
+	if err := UnsetNamespace("payments", WithRegistry(r)); err != nil {
+		t.Fatalf("UnsetNamespace() error = %v", err)
+	}
+
+	if _, err := Get[ExportedNamedTester](WithRegistry(r), WithNamespace("payments")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestProfiles_Options(t *testing.T) {
+	r := newTestReg(t)
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"NewRegistry WithProfile", func() error { _, err := NewRegistry(WithProfile("dev")); return err }(), ErrNotSupported},
+		{"NewRegistry empty profile", func() error { _, err := NewRegistry(WithActiveProfiles("")); return err }(), ErrBadOption},
+		{"NewRegistry twice", func() error { _, err := NewRegistry(WithActiveProfiles("a"), WithActiveProfiles("b")); return err }(), ErrBadOption},
+		{"Get WithActiveProfiles", func() error { _, err := Get[int](WithRegistry(r), WithActiveProfiles("dev")); return err }(), ErrNotSupported},
+		{"Set empty profile", Set(1, WithRegistry(r), WithProfile("")), ErrBadOption},
+		{"Set WithAlias", Set(1, WithRegistry(r), WithProfile("dev"), WithAlias("x")), ErrNotSupported},
+		{"Swap", func() error { _, _, err := Swap(1, WithRegistry(r), WithProfile("dev")); return err }(), ErrNotSupported},
+		{"GetAll", func() error { _, err := GetAll(WithRegistry(r), WithProfile("dev")); return err }(), ErrNotSupported},
+		{"GetMatching", func() error { _, err := GetMatching[int](Glob("*"), WithRegistry(r), WithProfile("dev")); return err }(), ErrNotSupported},
+	}
+
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Fatalf("%s err = %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+}
@@ -33,6 +33,8 @@ package reg
 import (
 	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 	"sync/atomic"
 
 	"github.com/mp3cko/registry/access"
@@ -78,6 +80,8 @@ func NewRegistry(opts ...Option) (*registry, error) {
 	}
 
 	reg.config.init.complete = true
+	reg.dropCallOpts()
+	reg.summarize()
 
 	return reg, nil
 }
@@ -107,110 +111,187 @@ func SetDefaultRegistry(r *registry) {
 //		WithName("ExternalService"),
 //	)
 func Set[T any](val T, opts ...Option) error {
-	r := defReg.Load()
-	r.mu.Lock()
+	rt := reflect.TypeFor[T]()
 
-	if err := applyOptions(r, unwrapOptions(opts)...); err != nil {
-		r.mu.Unlock()
+	r, err := acquire(OpSet, rt, opts)
+	if err != nil {
 		return err
 	}
 
-	if r.callOptions.withRegistry != nil {
-		withReg := r.callOptions.withRegistry
-		callOpts := r.callOptions.withoutRegistry()
+	if r.callOptions.conditions != nil {
+		return setConditional(r, rt, val, nil)
+	}
 
-		r.dropCallOpts()
-		r.mu.Unlock()
+	defer r.cleanup()
 
-		r = withReg
-		r.mu.Lock()
-		r.callOptions = callOpts
+	err = checkMutationOpts(OpSet, r.callOptions)
+	if err == nil {
+		err = setType(r, val)
 	}
 
-	defer r.mu.Unlock()
+	r.observe(OpSet, rt, err)
 
-	return setType(r, val)
+	return err
 }
 
 // Get retrieves the registered instance from a registry.
 // If no options are provided it will return the default registered instance or ErrNotFound if it doesn't exist.
 // Its behavior can be modified by passing in options (WithName, WithRegistry...)
 func Get[T any](opts ...Option) (T, error) {
-	r := defReg.Load()
-	r.mu.Lock()
+	rt := reflect.TypeFor[T]()
 
-	if err := applyOptions(r, unwrapOptions(opts)...); err != nil {
-		r.cleanup()
+	r, err := acquire(OpGet, rt, opts)
+	if err != nil {
 		return zeroValue[T](), err
 	}
 
-	if r.callOptions.uniqueName {
-		r.cleanup()
-		return zeroValue[T](), fmt.Errorf("Get WithUniqueNames: %w", ErrNotSupported)
+	defer r.cleanup()
+
+	val := zeroValue[T]()
+
+	err = checkGetOpts(r.callOptions)
+	if err == nil {
+		val, err = getType[T](r)
 	}
 
-	if r.callOptions.withRegistry != nil {
-		withReg := r.callOptions.withRegistry
-		callOpts := r.callOptions.withoutRegistry()
+	r.observe(OpGet, rt, err)
 
-		r.dropCallOpts()
-		r.mu.Unlock()
+	return val, err
+}
 
-		r = withReg
-		r.mu.Lock()
-		r.callOptions = callOpts
+// checkGetOpts returns an error if the call options are not supported by Get
+func checkGetOpts(co *callOptions) error {
+	if co.uniqueName {
+		return fmt.Errorf("Get WithUniqueNames: %w", ErrNotSupported)
 	}
 
-	defer r.cleanup()
+	if co.namePattern != nil {
+		return fmt.Errorf("Get WithNamePattern: %w, use GetMatching instead", ErrNotSupported)
+	}
+
+	if co.aliases != nil {
+		return fmt.Errorf("Get WithAlias: %w", ErrNotSupported)
+	}
+
+	if co.conditions != nil {
+		return fmt.Errorf("Get WithCondition: %w", ErrNotSupported)
+	}
+
+	if co.primary || co.prioritized {
+		return fmt.Errorf("Get WithPrimary or WithPriority: %w", ErrNotSupported)
+	}
+
+	if co.nonNil {
+		return fmt.Errorf("Get WithNonNil: %w, nothing is registered", ErrNotSupported)
+	}
 
-	return getType[T](r)
+	return nil
 }
 
 func GetAll(opts ...Option) (map[reflect.Type]map[string]any, error) {
-	r := defReg.Load()
-	r.mu.Lock()
+	r, err := acquire(OpGetAll, nil, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := applyOptions(r, unwrapOptions(opts)...); err != nil {
-		r.cleanup()
+	defer r.cleanup()
+
+	if err := checkGetAllOpts(r.callOptions); err != nil {
+		r.observe(OpGetAll, nil, err)
 		return nil, err
 	}
 
-	if r.callOptions.uniqueName {
-		r.cleanup()
-		return nil, fmt.Errorf("GetAll WithUniqueName: %w, use WithUniqueType instead", ErrNotSupported)
+	return getAll(r), nil
+}
+
+// checkGetAllOpts returns an error if the call options are not supported by GetAll
+func checkGetAllOpts(co *callOptions) error {
+	if co.uniqueName {
+		return fmt.Errorf("GetAll WithUniqueName: %w, use WithUniqueType instead", ErrNotSupported)
 	}
 
-	if r.callOptions.withRegistry != nil {
-		withReg := r.callOptions.withRegistry
-		callOpts := r.callOptions.withoutRegistry()
+	if co.aliases != nil {
+		return fmt.Errorf("GetAll WithAlias: %w", ErrNotSupported)
+	}
 
-		r.dropCallOpts()
-		r.mu.Unlock()
+	if co.profile != "" {
+		return fmt.Errorf("GetAll WithProfile: %w", ErrNotSupported)
+	}
 
-		r = withReg
-		r.mu.Lock()
-		r.callOptions = callOpts
+	if co.conditions != nil {
+		return fmt.Errorf("GetAll WithCondition: %w", ErrNotSupported)
 	}
 
-	defer r.cleanup()
+	if co.primary || co.prioritized {
+		return fmt.Errorf("GetAll WithPrimary or WithPriority: %w", ErrNotSupported)
+	}
 
-	return getAll(r), nil
+	if co.nonNil {
+		return fmt.Errorf("GetAll WithNonNil: %w, nothing is registered", ErrNotSupported)
+	}
+
+	return nil
 }
 
 func Unset[T any](val T, opts ...Option) error {
-	r := defReg.Load()
-	r.mu.Lock()
+	rt := reflect.TypeFor[T]()
 
-	if err := applyOptions(r, unwrapOptions(opts)...); err != nil {
-		r.cleanup()
+	r, err := acquire(OpUnset, rt, opts)
+	if err != nil {
 		return err
 	}
 
-	if r.callOptions.uniqueName {
-		r.cleanup()
+	defer r.cleanup()
+
+	err = checkUnsetOpts(r.callOptions)
+	if err == nil {
+		err = unsetType(r, val)
+	}
+
+	r.observe(OpUnset, rt, err)
+
+	return err
+}
+
+// checkUnsetOpts returns an error if the call options are not supported by Unset
+func checkUnsetOpts(co *callOptions) error {
+	if co.uniqueName {
 		return fmt.Errorf("Unset WithUniqueName: %w, use WithUniqueType instead", ErrNotSupported)
 	}
 
+	if co.namePattern != nil {
+		return fmt.Errorf("Unset WithNamePattern: %w", ErrNotSupported)
+	}
+
+	if co.aliases != nil {
+		return fmt.Errorf("Unset WithAlias: %w", ErrNotSupported)
+	}
+
+	if co.conditions != nil {
+		return fmt.Errorf("Unset WithCondition: %w", ErrNotSupported)
+	}
+
+	if co.primary || co.prioritized {
+		return fmt.Errorf("Unset WithPrimary or WithPriority: %w", ErrNotSupported)
+	}
+
+	if co.nonNil {
+		return fmt.Errorf("Unset WithNonNil: %w, nothing is registered", ErrNotSupported)
+	}
+
+	return nil
+}
+
+// acquire applies the call options and returns the registry the op should run on (the default one or the one passed using WithRegistry).
+//
+// On success the returned registry is locked and holds the call options, caller must release it using cleanup. On error nothing is left locked,
+// the rejected op on rt is reported like any other failed op.
+func acquire(op Op, rt reflect.Type, opts []Option) (*registry, error) {
+	r := defReg.Load()
+	r.lock(op)
+
+	err := applyOptions(r, unwrapOptions(opts)...)
+
 	if r.callOptions.withRegistry != nil {
 		withReg := r.callOptions.withRegistry
 		callOpts := r.callOptions.withoutRegistry()
@@ -218,70 +299,215 @@ func Unset[T any](val T, opts ...Option) error {
 		r.cleanup()
 
 		r = withReg
-		r.mu.Lock()
+		r.lock(op)
 		r.callOptions = callOpts
 	}
 
-	defer r.cleanup()
+	// checks only run in Health
+	if err == nil && r.callOptions.checkTimeout != 0 && op != OpHealth {
+		err = fmt.Errorf("%s WithCheckTimeout: %w", op, ErrNotSupported)
+	}
+
+	if err != nil {
+		r.observe(op, rt, err)
+		r.cleanup()
+
+		return nil, err
+	}
+
+	return r, nil
+}
 
-	return unsetType(r, val)
+// observe reports the outcome of an op on rt to the configured Metrics and Logger, caller must hold the lock and the call options
+func (t *registry) observe(op Op, rt reflect.Type, err error) {
+	t.observeName(op, rt, t.entryName(), err)
+}
+
+// observeName is observe for ops not targeting a single instance name (ex. UnsetNamespace), caller must hold the lock and the call options
+func (t *registry) observeName(op Op, rt reflect.Type, name string, err error) {
+	t.measure(op, rt, name, err)
+	t.log(op, rt, name, err)
 }
 
 // setType in registry, caller must handle mutex locking
 func setType[T any](r *registry, val T) error {
-	if r.callOptions == nil {
-		r.ensureCallOpts()
-	}
+	return setEntry(r, reflect.TypeFor[T](), val, nil, false)
+}
+
+// setEntry registers val under rt, the additional types (as) and all aliases (WithAlias) at once, caller must handle mutex locking.
+//
+// All the constraints are checked before anything is registered so on error the registry is left unchanged.
+// If replace is set existing instances are explicitly replaced, so they don't violate the uniqueness constraints.
+func setEntry(r *registry, rt reflect.Type, val any, as []reflect.Type, replace bool) error {
+	r.ensureCallOpts()
+
 	// take snapshots to avoid surprises if callOptions gets dropped later
 	co := r.callOptions
 	cfg := r.config
 
-	name := valueOrDefault(co.name, cfg.defaultName)
+	name := r.entryName()
+	names := []string{name}
+	for _, alias := range co.aliases {
+		names = append(names, joinName(r.namespace(), alias))
+	}
+
+	types := []reflect.Type{rt}
+	for _, t := range as {
+		if err := assignable(val, t); err != nil {
+			return err
+		}
+
+		types = append(types, t)
+	}
+
+	// explicit replacements keep the links of the replaced instance, the new value must be retrievable using all of them
+	links := r.keptLinks(entryKey{rt, name}, as, replace)
+	validated := slices.Clone(types)
+	for _, link := range links {
+		if slices.Contains(validated, link.rt) {
+			continue
+		}
+
+		if err := assignable(val, link.rt); err != nil {
+			return err
+		}
+
+		validated = append(validated, link.rt)
+	}
 
 	typeMustBeUnique := cfg.uniqueTypes || co.uniqueType
 	nameMustBeUnique := cfg.uniqueNames || co.uniqueName
 
-	rt := reflect.TypeFor[T]()
-
-	typeNamedness, typeAccessibility := access.Info(zeroValue[T]())
+	if co.profile != "" && (len(names)+len(types) > 2 || co.primary || co.prioritized) {
+		return fmt.Errorf("Set '%s' for profile '%s' WithAlias, SetAs, WithPrimary or WithPriority: %w", rt, co.profile, ErrNotSupported)
+	}
 
-	requiredAccessibility := max(cfg.accessibility, co.accessibility)
-	if typeAccessibility < requiredAccessibility {
-		return fmt.Errorf("Set '%T' failed: %w. Wanted at least '%s' but got '%s'", val, ErrAccessibilityTooLow, requiredAccessibility, typeAccessibility)
+	for _, t := range types {
+		if err := checkType(r, t); err != nil {
+			return err
+		}
 	}
 
-	requiredNamedness := max(cfg.namedness, co.namedness)
-	if typeNamedness < requiredNamedness {
-		return fmt.Errorf("Set '%T' failed: %w. Wanted at least '%s' but got '%s'", val, ErrNamednessTooLow, requiredNamedness, typeNamedness)
+	if err := validateValue(r, validated, val); err != nil {
+		return err
 	}
 
-	if _, ok := r.store[rt]; !ok {
-		r.store[rt] = map[string]any{}
+	if co.profile != "" {
+		return setProfiled(r, entryKey{rt, name}, val, co.profile, typeMustBeUnique, nameMustBeUnique && !replace)
 	}
 
-	if typeMustBeUnique && len(r.store[rt]) != 0 {
-		if name != "" {
-			return fmt.Errorf("Set '%T' named '%s' failed: %w", val, name, ErrNotUniqueType)
+	var keys []entryKey
+	seen := map[entryKey]bool{}
+
+	for _, t := range types {
+		if co.primary {
+			if err := r.checkPrimary(t, name); err != nil {
+				return err
+			}
 		}
 
-		return fmt.Errorf("Set '%T' failed: %w", val, ErrNotUniqueType)
-	}
+		others := r.scopedLen(t)
+		if _, ok := r.store[t][name]; ok && replace {
+			others--
+		}
 
-	if _, ok := r.store[rt][name]; ok {
-		if nameMustBeUnique {
+		if typeMustBeUnique && others != 0 {
 			if name != "" {
-				return fmt.Errorf("Set '%T' named '%s' failed: %w", val, name, ErrNotUniqueName)
+				return fmt.Errorf("Set '%s' named '%s' failed: %w", t, name, ErrNotUniqueType)
+			}
+
+			return fmt.Errorf("Set '%s' failed: %w", t, ErrNotUniqueType)
+		}
+
+		for _, n := range names {
+			key := entryKey{t, n}
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			if _, ok := r.store[t][n]; ok && nameMustBeUnique && !replace {
+				if n != "" {
+					return fmt.Errorf("Set '%s' named '%s' failed: %w", t, n, ErrNotUniqueName)
+				}
+
+				return fmt.Errorf("Set '%s' failed: %w", t, ErrNotUniqueName)
 			}
 
-			return fmt.Errorf("Set '%T' failed: %w", val, ErrNotUniqueName)
+			keys = append(keys, key)
+		}
+	}
+
+	// replaced instances lose their previous links, unless they are kept
+	if len(links) == 0 {
+		for _, key := range keys {
+			r.unlink(key)
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := r.store[key.rt]; !ok {
+			r.store[key.rt] = map[string]any{}
 		}
+
+		r.store[key.rt][key.name] = val
+		r.own(key)
+		r.rank(key, co.primary && key.name == name, replace)
 	}
 
-	r.store[rt][name] = val
+	for _, link := range links {
+		r.store[link.rt][link.name] = val
+	}
+
+	r.version++
+
+	if len(keys) > 1 {
+		canonical := keys[0]
+		r.metaFor(canonical).links = slices.Clone(keys[1:])
+
+		for _, link := range keys[1:] {
+			r.metaFor(link).canonical = &canonical
+		}
+	}
 
 	return nil
 }
 
+// checkType checks rt against the accessibility, namedness and policy requirements of the registry and the call
+func checkType(r *registry, rt reflect.Type) error {
+	co := r.callOptions
+	cfg := r.config
+
+	caller := r.caller()
+	typeNamedness, typeAccessibility := access.InfoFrom(rt, caller)
+
+	requiredAccessibility := max(cfg.accessibility, co.accessibility)
+	if typeAccessibility < requiredAccessibility {
+		explanation := access.ExplainLevel(rt, caller, requiredAccessibility)
+
+		return fmt.Errorf("Set '%s' failed: %w. Wanted at least '%s' but got '%s': %s", rt, ErrAccessibilityTooLow, requiredAccessibility, typeAccessibility, strings.Join(explanation, "; "))
+	}
+
+	requiredNamedness := max(cfg.namedness, co.namedness)
+	typeNamedness = refineNamedness(rt, typeNamedness, requiredNamedness)
+
+	if typeNamedness < requiredNamedness {
+		return fmt.Errorf("Set '%s' failed: %w. Wanted at least '%s' but got '%s'", rt, ErrNamednessTooLow, requiredNamedness, typeNamedness)
+	}
+
+	return checkPolicies(r, rt)
+}
+
+// refineNamedness returns DefinedType for named types defined in a package if it is the required namedness, access.Info reports them as NamedType
+func refineNamedness(rt reflect.Type, n, required access.Namedness) access.Namedness {
+	if required == access.DefinedType && n == access.NamedType && access.Classify(rt).Has(access.Defined) {
+		return access.DefinedType
+	}
+
+	return n
+}
+
 // unsetType from the registry, caller must handle mutex locking
 func unsetType[T any](r *registry, val T) error {
 	r.ensureCallOpts()
@@ -291,15 +517,23 @@ func unsetType[T any](r *registry, val T) error {
 
 	typeMustBeUnique := co.uniqueType
 
-	name := valueOrDefault(co.name, cfg.defaultName)
+	name := r.entryName()
 	rt := reflect.TypeFor[T]()
 
+	if co.profile != "" {
+		if !r.unsetProfiled(entryKey{rt, name}, co.profile) {
+			return fmt.Errorf("Unset '%T' for profile '%s' failed: %w", val, co.profile, ErrNotFound)
+		}
+
+		return nil
+	}
+
 	instances, ok := r.store[rt]
 	if !ok {
 		return fmt.Errorf("Unset '%T' failed: %w", val, ErrNotFound)
 	}
 
-	if typeMustBeUnique && len(instances) > 1 {
+	if typeMustBeUnique && r.scopedLen(rt) > 1 {
 		return fmt.Errorf("Unset '%T' WithUniqueType failed: %w", val, ErrNotUniqueType)
 	}
 
@@ -311,11 +545,7 @@ func unsetType[T any](r *registry, val T) error {
 		return fmt.Errorf("Unset '%T' failed: %w", val, ErrNotFound)
 	}
 
-	if len(instances) > 1 {
-		delete(instances, name)
-	} else {
-		delete(r.store, rt)
-	}
+	r.removeEntry(entryKey{rt, name})
 
 	return nil
 }
@@ -333,17 +563,10 @@ func getType[T any](r *registry) (T, error) {
 
 	typeMustBeUnique := cfg.uniqueTypes || co.uniqueType
 
-	name := valueOrDefault(co.name, cfg.defaultName)
+	name := r.entryName()
 	rt := reflect.TypeFor[T]()
 
-	instances, ok := r.store[rt]
-	if !ok {
-		z := zeroValue[T]()
-
-		return z, fmt.Errorf("Get '%T' failed: %w", z, ErrNotFound)
-	}
-
-	if typeMustBeUnique && len(instances) > 1 {
+	if typeMustBeUnique && r.scopedLen(rt) > 1 {
 		z := zeroValue[T]()
 		if name != "" {
 			return z, fmt.Errorf("Get '%T' named '%s' failed: %w", z, name, ErrNotUniqueType)
@@ -352,7 +575,23 @@ func getType[T any](r *registry) (T, error) {
 		return z, fmt.Errorf("Get '%T' failed: %w", zeroValue[T](), ErrNotUniqueType)
 	}
 
-	val, ok := r.store[rt][name]
+	key := entryKey{rt, name}
+	if co.name == "" {
+		var ambiguous bool
+		if key, ambiguous = r.preferred(rt); ambiguous {
+			return zeroValue[T](), fmt.Errorf("Get '%T' failed, multiple instances share the highest priority: %w", zeroValue[T](), ErrNotUniqueType)
+		}
+	}
+
+	if err := r.depend(key); err != nil {
+		return zeroValue[T](), err
+	}
+
+	val, ok, conflicts := r.resolve(key)
+	if conflicts != nil {
+		return zeroValue[T](), fmt.Errorf("Get '%T' failed, provided by profiles %q: %w", zeroValue[T](), conflicts, ErrProfileConflict)
+	}
+
 	if !ok {
 		z := zeroValue[T]()
 		if name == "" {
@@ -362,18 +601,28 @@ func getType[T any](r *registry) (T, error) {
 		return z, fmt.Errorf("Get '%T' named '%s' failed: %w", z, name, ErrNotFound)
 	}
 
-	return val.(T), nil
+	// nil interfaces don't assert
+	out, _ := val.(T)
+
+	return out, nil
 }
 
 // getAll returns all registered instances, filtered by callopts from r
 func getAll(r *registry) map[reflect.Type]map[string]any {
+	return filtered(r).store
+}
+
+// filtered returns a registry holding the instances (including the ones registered for profiles) of r, filtered by callopts from r
+func filtered(r *registry) *registry {
 	defer r.dropCallOpts()
 
 	stub := &registry{
 		config: r.config.clone(),
 	}
+	// names are returned relative to the namespace
+	stub.config.namespace = ""
 
 	cloneEntries(r, stub)
 
-	return stub.store
+	return stub
 }
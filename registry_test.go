@@ -267,6 +267,56 @@ func TestRegistry(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "defined namedness rejects predeclared types",
+			testFunc: func(tt *testing.T) {
+				r, err := NewRegistry(WithNamedness(access.DefinedType))
+				if err != nil {
+					tt.Fatalf("NewRegistry WithNamedness error = %v", err)
+				}
+
+				if err := Set("addr", WithRegistry(r)); !errors.Is(err, ErrNamednessTooLow) {
+					tt.Fatalf("Set string err = %v, want ErrNamednessTooLow", err)
+				}
+
+				if err := Set[error](nil, WithRegistry(r)); !errors.Is(err, ErrNamednessTooLow) {
+					tt.Fatalf("Set error err = %v, want ErrNamednessTooLow", err)
+				}
+
+				if err := Set(&ExportedNamedTester{}, WithRegistry(r)); err != nil {
+					tt.Fatalf("Set pointer to defined type error = %v", err)
+				}
+
+				if err := Set(1, WithRegistry(r), WithNamedness(access.NamedType)); !errors.Is(err, ErrNamednessTooLow) {
+					tt.Fatalf("Set int with lower call namedness err = %v, want ErrNamednessTooLow", err)
+				}
+			},
+		},
+		{
+			name: "GetAll filters by namedness",
+			testFunc: func(tt *testing.T) {
+				r := newTestReg(tt)
+
+				if err := Set(1, WithRegistry(r)); err != nil {
+					tt.Fatalf("Set int error = %v", err)
+				}
+
+				if err := Set(ExportedNamedTester{}, WithRegistry(r)); err != nil {
+					tt.Fatalf("Set defined type error = %v", err)
+				}
+
+				if err := Set([]int{}, WithRegistry(r)); err != nil {
+					tt.Fatalf("Set anonymous type error = %v", err)
+				}
+
+				for namedness, want := range map[access.Namedness]int{access.AnonymousType: 1, access.NamedType: 2, access.DefinedType: 1} {
+					all, err := GetAll(WithRegistry(r), WithNamedness(namedness))
+					if err != nil || len(all) != want {
+						tt.Fatalf("GetAll(WithNamedness(%s)) = %v, %v; want %d types", namedness, all, err, want)
+					}
+				}
+			},
+		},
 		{
 			name: "default name in constructor applies to set and get",
 			testFunc: func(tt *testing.T) {
@@ -1,8 +1,13 @@
 package reg
 
 import (
+	"log/slog"
+	"maps"
 	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/mp3cko/registry/access"
 )
@@ -13,18 +18,57 @@ type registry struct {
 	mu sync.Mutex
 	// store maps a type to a map[name]instance. Default name is an empty string.
 	store       map[reflect.Type]map[string]any
+	meta        map[entryKey]*entryMeta         // metadata of instances, present only for instances that have any
+	profiles    map[entryKey]map[string]any     // instances registered using WithProfile, by profile
+	collections map[entryKey]*collection        // multi-bindings registered using Append and Put, keyed by their slice or map type
+	inflight    map[entryKey]*pendingCall       // GetOrCreate constructor calls in progress
+	building    map[uint64][]entryKey           // instances being built by factories, per goroutine
+	deps        map[entryKey]map[entryKey]bool  // dependencies resolved by factories, see Graph
+	version     uint64                          // incremented on every change of the entries
+	modules     []Module                        // installed modules, in order of installation
+	installing  Module                          // module currently registering into this (staging) registry
+	pending     []*pendingSet                   // conditional registrations waiting for Resolve
+	resolved    bool                            // Resolve was called, conditional registrations are evaluated immediately
+	summary     atomic.Pointer[registrySummary] // entry counts as of the last unlock, see LogValue
 	config      *registryConfig
 	callOptions *callOptions
 }
 
+// Registry is a registry instance, as returned by [NewRegistry]. Use it to name registries in signatures, ex. in [Module.Register]
+type Registry = *registry
+
+// entryKey identifies a single instance
+type entryKey struct {
+	rt   reflect.Type
+	name string
+}
+
+// entryMeta holds the metadata of a single instance
+type entryMeta struct {
+	canonical   *entryKey  // instance this one was registered together with (an alias or additional type), nil for canonical instances
+	links       []entryKey // aliases and additional types registered together with this instance
+	owner       Module     // module that registered the instance, nil if registered directly
+	primary     bool       // preferred by unnamed lookups, see WithPrimary
+	priority    int        // see WithPriority
+	prioritized bool       // registered using WithPriority
+}
+
 // registryConfig holds the configuration for the registry.
 type registryConfig struct {
-	init          initOpts             // initialization options
-	defaultName   string               // default name for new instances
-	uniqueTypes   bool                 // enforce single instance per type
-	uniqueNames   bool                 // enforce unique names per type
-	accessibility access.Accessibility // enforce type accessibility
-	namedness     access.Namedness     // enforce type namedness
+	init          initOpts                        // initialization options
+	defaultName   string                          // default name for new instances
+	uniqueTypes   bool                            // enforce single instance per type
+	uniqueNames   bool                            // enforce unique names per type
+	accessibility access.Accessibility            // enforce type accessibility
+	namedness     access.Namedness                // enforce type namedness
+	metrics       Metrics                         // receives op measurements, nil disables them
+	logger        *slog.Logger                    // logs mutations and failed lookups, nil disables logging
+	logLevels     map[Op]slog.Level               // per op log levels, see defaultLogLevels
+	namespace     string                          // prefix for all instance names
+	nonNil        bool                            // reject nil values
+	validators    map[reflect.Type][]validateFunc // per type validators
+	policies      []Policy                        // type level rules
+	profiles      []string                        // active profiles, see WithActiveProfiles
 }
 
 type initOpts struct {
@@ -33,6 +77,10 @@ type initOpts struct {
 	uniqueNamesSet   bool // indicates that the registry was initialized using WithUniqueName
 	accessibilitySet bool // indicates that the registry was initialized using WithAccessibility
 	namednessSet     bool // indicates that the registry was initialized using WithNamedness
+	metricsSet       bool // indicates that the registry was initialized using WithMetrics
+	loggerSet        bool // indicates that the registry was initialized using WithLogger
+	nonNilSet        bool // indicates that the registry was initialized using WithNonNil
+	profilesSet      bool // indicates that the registry was initialized using WithActiveProfiles
 }
 
 // callOptions holds the options for a single call to the registry.
@@ -43,11 +91,139 @@ type callOptions struct {
 	withRegistry  *registry            // use instead of default registry
 	accessibility access.Accessibility // type accessibility requirement
 	namedness     access.Namedness     // type namedness requirement
+	namePattern   *NamePattern         // instance name filter
+	namespace     string               // prefix for instance names, relative to the registry namespace
+	aliases       []string             // additional instance names
+	nonNil        bool                 // reject nil values
+	callerPkg     string               // package accessibility is computed relative to
+	callerSkip    int                  // frames to skip above the first caller outside this package
+	profile       string               // profile the instance is registered for (or retrieved from)
+	conditions    []Condition          // conditions the registration is guarded by
+	primary       bool                 // instance is preferred by unnamed lookups
+	priority      int                  // instance priority, higher first
+	prioritized   bool                 // priority was set
+	checkTimeout  time.Duration        // timeout of a single health check
+}
+
+// lock acquires the registry mutex, reporting the time spent waiting for it to the configured Metrics
+func (t *registry) lock(op Op) {
+	m := t.config.metrics
+	if m == nil {
+		t.mu.Lock()
+		return
+	}
+
+	start := time.Now()
+	t.mu.Lock()
+	m.LockWait(op, time.Since(start))
+}
+
+// unlock publishes the summary of the entries if they changed and releases the registry mutex
+func (t *registry) unlock() {
+	if s := t.summary.Load(); s == nil || s.version != t.version {
+		t.summarize()
+	}
+
+	t.mu.Unlock()
+}
+
+// registrySummary holds the entry counts of a registry, it is readable without the lock
+type registrySummary struct {
+	version uint64
+	types   int
+	entries int
+}
+
+// summarize publishes the summary of the entries, caller must hold the lock
+func (t *registry) summarize() {
+	s := &registrySummary{version: t.version, types: len(t.store)}
+	for _, instances := range t.store {
+		s.entries += len(instances)
+	}
+
+	t.summary.Store(s)
+}
+
+// metaFor returns the metadata of key, creating it if needed
+func (t *registry) metaFor(key entryKey) *entryMeta {
+	if t.meta == nil {
+		t.meta = map[entryKey]*entryMeta{}
+	}
+
+	m, ok := t.meta[key]
+	if !ok {
+		m = new(entryMeta)
+		t.meta[key] = m
+	}
+
+	return m
+}
+
+// removeEntry removes the instance together with its metadata, removing a canonical instance also removes all its links
+func (t *registry) removeEntry(key entryKey) {
+	t.unlink(key)
+	delete(t.meta, key)
+	// the dependencies of the removed instance are gone with it, its dependents keep depending on it (see DependencyGraph.Missing)
+	delete(t.deps, key)
+	t.version++
+
+	if instances, ok := t.store[key.rt]; ok {
+		delete(instances, key.name)
+
+		if len(instances) == 0 {
+			delete(t.store, key.rt)
+		}
+	}
+}
+
+// unlink detaches key from the instance it was registered with, if key is a canonical instance all its links are removed
+func (t *registry) unlink(key entryKey) {
+	m := t.meta[key]
+	if m == nil {
+		return
+	}
+
+	defer t.pruneMeta(key)
+
+	if m.canonical != nil {
+		canonical := *m.canonical
+		m.canonical = nil
+
+		if cm := t.meta[canonical]; cm != nil {
+			cm.links = slices.DeleteFunc(cm.links, func(link entryKey) bool { return link == key })
+			t.pruneMeta(canonical)
+		}
+
+		return
+	}
+
+	links := m.links
+	m.links = nil
+
+	for _, link := range links {
+		if lm := t.meta[link]; lm != nil {
+			lm.canonical = nil
+		}
+
+		t.removeEntry(link)
+	}
+}
+
+// pruneMeta drops the metadata of key if it holds nothing
+func (t *registry) pruneMeta(key entryKey) {
+	if m, ok := t.meta[key]; ok && m.empty() {
+		delete(t.meta, key)
+	}
+}
+
+// empty reports whether the metadata holds nothing
+func (t *entryMeta) empty() bool {
+	return t.canonical == nil && len(t.links) == 0 && t.owner == nil && !t.primary && !t.prioritized
 }
 
 func (t *registry) cleanup() {
 	t.dropCallOpts()
-	t.mu.Unlock()
+	t.unlock()
 }
 
 func (t *registry) ensureCallOpts() *callOptions {
@@ -66,6 +242,58 @@ func (t *registry) dropCallOpts() {
 	t.callOptions = nil
 }
 
+// entryName resolves the full instance name (including namespaces) targeted by the current call
+func (t *registry) entryName() string {
+	if t.callOptions == nil {
+		return joinName(t.config.namespace, t.config.defaultName)
+	}
+
+	return joinName(t.namespace(), valueOrDefault(t.callOptions.name, t.config.defaultName))
+}
+
+// caller resolves the package the current call is made from, accessibility is computed relative to it
+func (t *registry) caller() string {
+	co := t.callOptions
+	if co == nil {
+		return callerPackage()
+	}
+
+	if co.callerPkg != "" {
+		return co.callerPkg
+	}
+
+	return callerPackageSkip(co.callerSkip)
+}
+
+// namespace resolves the namespace targeted by the current call
+func (t *registry) namespace() string {
+	if t.callOptions == nil {
+		return t.config.namespace
+	}
+
+	return joinName(t.config.namespace, t.callOptions.namespace)
+}
+
+// scopedLen returns the number of instances of rt inside the namespace targeted by the current call, aliases of counted instances are skipped
+func (t *registry) scopedLen(rt reflect.Type) int {
+	ns := t.namespace()
+
+	var n int
+	for name := range t.store[rt] {
+		if _, ok := relName(ns, name); !ok {
+			continue
+		}
+
+		if m := t.meta[entryKey{rt, name}]; m != nil && m.canonical != nil && m.canonical.rt == rt {
+			continue
+		}
+
+		n++
+	}
+
+	return n
+}
+
 func (t *registryConfig) clone() *registryConfig {
 	if t == nil {
 		return nil
@@ -73,6 +301,16 @@ func (t *registryConfig) clone() *registryConfig {
 
 	clone := *t
 	clone.init.complete = false
+	clone.logLevels = maps.Clone(t.logLevels)
+	clone.policies = slices.Clone(t.policies)
+	clone.profiles = slices.Clone(t.profiles)
+
+	if t.validators != nil {
+		clone.validators = make(map[reflect.Type][]validateFunc, len(t.validators))
+		for rt, validators := range t.validators {
+			clone.validators[rt] = slices.Clone(validators)
+		}
+	}
 	return &clone
 }
 
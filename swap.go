@@ -0,0 +1,160 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Swap registers val like [Set] and returns the instance it replaced, if any.
+//
+// Unlike Set it replaces existing instances even if the registry enforces unique names, as the replacement is explicit.
+// It also keeps the aliases and additional types (see [WithAlias] and [SetAs]) of the replaced instance unless new aliases are passed,
+// val must be assignable to all of them otherwise ErrNotAssignable is returned. Swapping an alias detaches it from its instance.
+// Supports the same options as Set.
+func Swap[T any](val T, opts ...Option) (old T, existed bool, err error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpSwap, rt, opts)
+	if err != nil {
+		return old, false, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkMutationOpts(OpSwap, r.callOptions); err != nil {
+		r.observe(OpSwap, rt, err)
+		return old, false, err
+	}
+
+	old, existed = lookup[T](r)
+
+	err = setEntry(r, rt, val, nil, true)
+	r.observe(OpSwap, rt, err)
+
+	if err != nil {
+		return zeroValue[T](), false, err
+	}
+
+	return old, existed, nil
+}
+
+// CompareAndSwap replaces the instance with new only if it currently equals old, reporting whether it was replaced.
+//
+// Returns ErrNotFound if there is no instance to compare with and ErrNotSupported if the registered instance is not comparable (possible for interface types).
+// A registered nil interface equals a nil old.
+// Supports the same options as [Swap].
+func CompareAndSwap[T comparable](old, new T, opts ...Option) (swapped bool, err error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpCompareAndSwap, rt, opts)
+	if err != nil {
+		return false, err
+	}
+
+	defer r.cleanup()
+
+	if err := checkMutationOpts(OpCompareAndSwap, r.callOptions); err != nil {
+		r.observe(OpCompareAndSwap, rt, err)
+		return false, err
+	}
+
+	cur, ok := lookup[T](r)
+	if !ok {
+		err = fmt.Errorf("CompareAndSwap '%s' named '%s' failed: %w", rt, r.entryName(), ErrNotFound)
+		r.observe(OpCompareAndSwap, rt, err)
+
+		return false, err
+	}
+
+	// a nil interface is comparable, it equals a nil old
+	if any(cur) != nil && !reflect.ValueOf(any(cur)).Comparable() {
+		err = fmt.Errorf("CompareAndSwap '%s' failed: %w, registered '%T' is not comparable", rt, ErrNotSupported, cur)
+		r.observe(OpCompareAndSwap, rt, err)
+
+		return false, err
+	}
+
+	if cur != old {
+		return false, nil
+	}
+
+	err = setEntry(r, rt, new, nil, true)
+	r.observe(OpCompareAndSwap, rt, err)
+
+	return err == nil, err
+}
+
+// Update performs a read-modify-write of the instance under the registry lock.
+//
+// fn receives the current instance (or the zero value) and whether it exists, the instance it returns replaces the current one.
+// If fn returns an error the registry is left unchanged and the error is returned.
+//
+// fn runs while the registry is locked, it must not use the registry (deadlock) and should return quickly.
+//
+//	cfg, err := Update(func(old Config, exists bool) (Config, error) {
+//		old.Timeout = 5 * time.Second
+//		return old, nil
+//	})
+//
+// Supports the same options as [Swap].
+func Update[T any](fn func(old T, exists bool) (T, error), opts ...Option) (T, error) {
+	rt := reflect.TypeFor[T]()
+
+	r, err := acquire(OpUpdate, rt, opts)
+	if err != nil {
+		return zeroValue[T](), err
+	}
+
+	defer r.cleanup()
+
+	if err := checkMutationOpts(OpUpdate, r.callOptions); err != nil {
+		r.observe(OpUpdate, rt, err)
+		return zeroValue[T](), err
+	}
+
+	old, exists := lookup[T](r)
+
+	val, err := fn(old, exists)
+	if err != nil {
+		err = fmt.Errorf("Update '%s' failed: %w", rt, err)
+	} else {
+		err = setEntry(r, rt, val, nil, true)
+	}
+
+	r.observe(OpUpdate, rt, err)
+
+	if err != nil {
+		return zeroValue[T](), err
+	}
+
+	return val, nil
+}
+
+// lookup returns the instance of T targeted by the current call, caller must handle mutex locking
+func lookup[T any](r *registry) (T, bool) {
+	val, ok := r.store[reflect.TypeFor[T]()][r.entryName()]
+	if !ok {
+		return zeroValue[T](), false
+	}
+
+	out, _ := val.(T) // nil interfaces don't assert
+
+	return out, true
+}
+
+// checkMutationOpts returns an error if the call options are not supported by a mutating op
+func checkMutationOpts(op Op, co *callOptions) error {
+	if co.namePattern != nil {
+		return fmt.Errorf("%s WithNamePattern: %w", op, ErrNotSupported)
+	}
+
+	if co.profile != "" && op != OpSet {
+		return fmt.Errorf("%s WithProfile: %w", op, ErrNotSupported)
+	}
+
+	if co.conditions != nil && op != OpSet {
+		return fmt.Errorf("%s WithCondition: %w", op, ErrNotSupported)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package reg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type stringerTester struct{ ID int }
+
+func (t stringerTester) String() string {
+	return fmt.Sprint(t.ID)
+}
+
+func TestSwap(t *testing.T) {
+	r := newTestReg(t, WithUniqueName().WithUniqueType())
+
+	old, existed, err := Swap(ExportedNamedTester{ID: 1}, WithRegistry(r))
+	if err != nil || existed || old != (ExportedNamedTester{}) {
+		t.Fatalf("Swap into empty = %+v, %v, %v; want zero, false, nil", old, existed, err)
+	}
+
+	// explicit replacement is allowed despite the unique constraints
+	old, existed, err = Swap(ExportedNamedTester{ID: 2}, WithRegistry(r))
+	if err != nil || !existed || old.ID != 1 {
+		t.Fatalf("Swap existing = %+v, %v, %v; want ID 1, true, nil", old, existed, err)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 2 {
+		t.Fatalf("Get after Swap = %+v, want ID 2", got)
+	}
+
+	// another name is still a second instance of a unique type
+	if _, _, err := Swap(ExportedNamedTester{ID: 3}, WithRegistry(r).WithName("b")); !errors.Is(err, ErrNotUniqueType) {
+		t.Fatalf("Swap second instance err = %v, want ErrNotUniqueType", err)
+	}
+
+	if _, _, err := Swap(ExportedNamedTester{}, WithRegistry(r).WithNamePattern(Glob("*"))); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Swap WithNamePattern err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestSwap_KeepsLinks(t *testing.T) {
+	r := newTestReg(t)
+
+	stringer := []reflect.Type{reflect.TypeFor[fmt.Stringer]()}
+	if err := SetAs[any](stringerTester{ID: 1}, stringer, WithRegistry(r), WithAlias("old")); err != nil {
+		t.Fatalf("SetAs error = %v", err)
+	}
+
+	if _, _, err := Swap[any](stringerTester{ID: 2}, WithRegistry(r)); err != nil {
+		t.Fatalf("Swap error = %v", err)
+	}
+
+	if got, err := Get[fmt.Stringer](WithRegistry(r)); err != nil || got != (stringerTester{ID: 2}) {
+		t.Fatalf("Get additional type after Swap = %v, %v, want ID 2", got, err)
+	}
+
+	if got, err := Get[any](WithRegistry(r).WithName("old")); err != nil || got != (stringerTester{ID: 2}) {
+		t.Fatalf("Get alias after Swap = %v, %v, want ID 2", got, err)
+	}
+
+	// the replacement must be retrievable using all the links
+	if _, _, err := Swap[any](ExportedNamedTester{ID: 3}, WithRegistry(r)); !errors.Is(err, ErrNotAssignable) {
+		t.Fatalf("Swap unassignable err = %v, want ErrNotAssignable", err)
+	}
+
+	if got, _ := Get[any](WithRegistry(r)); got != (stringerTester{ID: 2}) {
+		t.Fatalf("Get after failed Swap = %v, want ID 2", got)
+	}
+
+	// passing aliases replaces the links
+	if _, _, err := Swap[any](ExportedNamedTester{ID: 4}, WithRegistry(r).WithAlias("new")); err != nil {
+		t.Fatalf("Swap WithAlias error = %v", err)
+	}
+
+	if _, err := Get[fmt.Stringer](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get dropped type err = %v, want ErrNotFound", err)
+	}
+
+	if got, err := Get[any](WithRegistry(r).WithName("new")); err != nil || got != (ExportedNamedTester{ID: 4}) {
+		t.Fatalf("Get new alias = %v, %v, want ID 4", got, err)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	r := newTestReg(t)
+
+	if _, err := CompareAndSwap(ExportedNamedTester{}, ExportedNamedTester{ID: 1}, WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("CompareAndSwap missing err = %v, want ErrNotFound", err)
+	}
+
+	MustSet(ExportedNamedTester{ID: 1}, WithRegistry(r))
+
+	swapped, err := CompareAndSwap(ExportedNamedTester{ID: 7}, ExportedNamedTester{ID: 2}, WithRegistry(r))
+	if err != nil || swapped {
+		t.Fatalf("CompareAndSwap stale = %v, %v; want false, nil", swapped, err)
+	}
+
+	swapped, err = CompareAndSwap(ExportedNamedTester{ID: 1}, ExportedNamedTester{ID: 2}, WithRegistry(r))
+	if err != nil || !swapped {
+		t.Fatalf("CompareAndSwap current = %v, %v; want true, nil", swapped, err)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 2 {
+		t.Fatalf("Get after CompareAndSwap = %+v, want ID 2", got)
+	}
+
+	MustSet[any]([]int{1}, WithRegistry(r))
+	if _, err := CompareAndSwap[any](nil, 1, WithRegistry(r)); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("CompareAndSwap non comparable err = %v, want ErrNotSupported", err)
+	}
+
+	MustSet[fmt.Stringer](nil, WithRegistry(r))
+	if got, err := Get[fmt.Stringer](WithRegistry(r)); err != nil || got != nil {
+		t.Fatalf("Get nil interface = %v, %v; want nil, nil", got, err)
+	}
+
+	if swapped, err := CompareAndSwap[fmt.Stringer](stringerTester{ID: 1}, stringerTester{ID: 2}, WithRegistry(r)); err != nil || swapped {
+		t.Fatalf("CompareAndSwap stale nil = %v, %v; want false, nil", swapped, err)
+	}
+
+	if swapped, err := CompareAndSwap[fmt.Stringer](nil, stringerTester{ID: 1}, WithRegistry(r)); err != nil || !swapped {
+		t.Fatalf("CompareAndSwap nil = %v, %v; want true, nil", swapped, err)
+	}
+
+	if got := MustGet[fmt.Stringer](WithRegistry(r)); got != (stringerTester{ID: 1}) {
+		t.Fatalf("Get after CompareAndSwap nil = %v, want ID 1", got)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	r := newTestReg(t)
+
+	inc := func(old ExportedNamedTester, _ bool) (ExportedNamedTester, error) {
+		old.ID++
+		return old, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := Update(inc, WithRegistry(r)); err != nil {
+				t.Errorf("Update error = %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 50 {
+		t.Fatalf("Get after concurrent updates = %+v, want ID 50", got)
+	}
+
+	errUpdate := errors.New("rejected")
+	_, err := Update(func(ExportedNamedTester, bool) (ExportedNamedTester, error) {
+		return ExportedNamedTester{ID: -1}, errUpdate
+	}, WithRegistry(r))
+	if !errors.Is(err, errUpdate) {
+		t.Fatalf("Update err = %v, want %v", err, errUpdate)
+	}
+
+	if got := MustGet[ExportedNamedTester](WithRegistry(r)); got.ID != 50 {
+		t.Fatalf("failed Update changed the instance: %+v", got)
+	}
+
+	got, err := Update(func(old ExportedNamedTester, exists bool) (ExportedNamedTester, error) {
+		if exists {
+			t.Fatalf("Update of missing instance reported it exists: %+v", old)
+		}
+
+		return ExportedNamedTester{ID: 9}, nil
+	}, WithRegistry(r).WithName("new"))
+	if err != nil || got.ID != 9 {
+		t.Fatalf("Update missing = %+v, %v", got, err)
+	}
+}
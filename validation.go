@@ -0,0 +1,88 @@
+package reg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validator is implemented by values that can check their own invariants (ex. config structs).
+//
+// Values implementing it are validated automatically by every op that registers them, failures are returned wrapped in ErrInvalidValue.
+// Values whose pointer implements it (pointer receiver) are validated through a copy, so changes Validate makes are not registered.
+type Validator interface {
+	Validate() error
+}
+
+// validateFunc is a type erased validator registered using WithValidator
+type validateFunc func(val any) error
+
+// validateValue runs all the validators that apply to val registered under types, caller must handle mutex locking
+func validateValue(r *registry, types []reflect.Type, val any) error {
+	rt := types[0]
+
+	if (r.config.nonNil || r.callOptions.nonNil) && isNil(val) {
+		return fmt.Errorf("Set '%s' failed: %w: nil value", rt, ErrInvalidValue)
+	}
+
+	if v, ok := validatorOf(val); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("Set '%s' failed: %w: %w", rt, ErrInvalidValue, err)
+		}
+	}
+
+	for _, t := range types {
+		for _, validate := range r.config.validators[t] {
+			if err := validate(val); err != nil {
+				return fmt.Errorf("Set '%s' failed: %w: %w", t, ErrInvalidValue, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatorOf returns the Validator implemented by val or, for pointer receivers, by a pointer to a copy of val
+func validatorOf(val any) (Validator, bool) {
+	if isNil(val) {
+		return nil, false
+	}
+
+	if v, ok := val.(Validator); ok {
+		return v, true
+	}
+
+	rt := reflect.TypeOf(val)
+	if rt.Kind() == reflect.Pointer || !reflect.PointerTo(rt).Implements(reflect.TypeFor[Validator]()) {
+		return nil, false
+	}
+
+	ptr := reflect.New(rt)
+	ptr.Elem().Set(reflect.ValueOf(val))
+
+	return ptr.Interface().(Validator), true
+}
+
+// newValidateFunc erases the type of a validator for T
+func newValidateFunc[T any](fn func(T) error) validateFunc {
+	return func(val any) error {
+		if val == nil {
+			return fn(zeroValue[T]())
+		}
+
+		return fn(val.(T))
+	}
+}
+
+// isNil reports whether val is nil or holds a nil pointer, map, func, chan or interface
+func isNil(val any) bool {
+	if val == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(val); rv.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,148 @@
+package reg
+
+import (
+	"errors"
+	"testing"
+)
+
+// ValidatedConfig implements Validator
+type ValidatedConfig struct{ Port int }
+
+func (t ValidatedConfig) Validate() error {
+	if t.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+
+	return nil
+}
+
+func TestValidation_ValidatorInterface(t *testing.T) {
+	r := newTestReg(t)
+
+	if err := Set(ValidatedConfig{}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set invalid config err = %v, want ErrInvalidValue", err)
+	}
+
+	if _, err := Get[ValidatedConfig](WithRegistry(r)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("invalid config was registered, Get err = %v", err)
+	}
+
+	if err := Set(ValidatedConfig{Port: 80}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set valid config error = %v", err)
+	}
+
+	// Validator is checked on every registering op
+	if _, _, err := Swap(ValidatedConfig{}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Swap invalid config err = %v, want ErrInvalidValue", err)
+	}
+}
+
+// PointerValidatedConfig implements Validator with a pointer receiver
+type PointerValidatedConfig struct {
+	Port int `env:"PORT"`
+}
+
+func (t *PointerValidatedConfig) Validate() error {
+	if t.Port <= 0 {
+		return errors.New("port must be positive")
+	}
+
+	return nil
+}
+
+func TestValidation_PointerReceiver(t *testing.T) {
+	r := newTestReg(t)
+
+	if err := Set(PointerValidatedConfig{}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set invalid config err = %v, want ErrInvalidValue", err)
+	}
+
+	if err := Set(PointerValidatedConfig{Port: 80}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set valid config error = %v", err)
+	}
+
+	if err := Set(&PointerValidatedConfig{}, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set invalid config pointer err = %v, want ErrInvalidValue", err)
+	}
+
+	// nil pointers are not validated
+	if err := Set[*PointerValidatedConfig](nil, WithRegistry(r)); err != nil {
+		t.Fatalf("Set nil config pointer error = %v", err)
+	}
+
+	if _, err := BindConfig[PointerValidatedConfig]([]Source{Map(map[string]string{"PORT": "0"})}, WithRegistry(r), WithName("bound")); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("BindConfig invalid config err = %v, want ErrInvalidValue", err)
+	}
+}
+
+func TestValidation_WithValidator(t *testing.T) {
+	errNegative := errors.New("negative id")
+	r := newTestReg(t, WithValidator(func(v ExportedNamedTester) error {
+		if v.ID < 0 {
+			return errNegative
+		}
+
+		return nil
+	}))
+
+	err := Set(ExportedNamedTester{ID: -1}, WithRegistry(r))
+	if !errors.Is(err, ErrInvalidValue) || !errors.Is(err, errNegative) {
+		t.Fatalf("Set invalid err = %v, want ErrInvalidValue wrapping %v", err, errNegative)
+	}
+
+	if err := Set(ExportedNamedTester{ID: 1}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set valid error = %v", err)
+	}
+
+	// validators are cloned with the config
+	clone := newTestReg(t, WithCloneConfig(r))
+	if err := Set(ExportedNamedTester{ID: -1}, WithRegistry(clone)); !errors.Is(err, errNegative) {
+		t.Fatalf("Set invalid in clone err = %v, want %v", err, errNegative)
+	}
+
+	if err := Set(ExportedNamedTester{}, WithRegistry(r), WithValidator(func(ExportedNamedTester) error { return nil })); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Set WithValidator err = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestValidation_WithNonNil(t *testing.T) {
+	r := newTestReg(t, WithNonNil())
+
+	if err := Set[*ExportedNamedTester](nil, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set nil pointer err = %v, want ErrInvalidValue", err)
+	}
+
+	if err := Set[error](nil, WithRegistry(r)); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set nil interface err = %v, want ErrInvalidValue", err)
+	}
+
+	if err := Set(&ExportedNamedTester{}, WithRegistry(r)); err != nil {
+		t.Fatalf("Set non nil pointer error = %v", err)
+	}
+
+	r2 := newTestReg(t)
+	if err := Set[*ExportedNamedTester](nil, WithRegistry(r2)); err != nil {
+		t.Fatalf("Set nil without WithNonNil error = %v", err)
+	}
+
+	if err := Set[*ExportedNamedTester](nil, WithRegistry(r2).WithNonNil().WithName("x")); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("Set nil WithNonNil call option err = %v, want ErrInvalidValue", err)
+	}
+
+	// lookups of a registry rejecting nil values work, passing WithNonNil to them doesn't
+	if _, err := Get[*ExportedNamedTester](WithRegistry(r)); err != nil {
+		t.Fatalf("Get from a WithNonNil registry error = %v", err)
+	}
+
+	if _, err := Get[*ExportedNamedTester](WithRegistry(r2).WithNonNil()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Get WithNonNil err = %v, want ErrNotSupported", err)
+	}
+
+	if _, err := GetAll(WithRegistry(r2).WithNonNil()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("GetAll WithNonNil err = %v, want ErrNotSupported", err)
+	}
+
+	if err := Unset[*ExportedNamedTester](nil, WithRegistry(r2).WithNonNil()); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("Unset WithNonNil err = %v, want ErrNotSupported", err)
+	}
+}